@@ -1,6 +1,7 @@
 package dag
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -71,3 +72,17 @@ func (suite *AncestorsTestSuite) TestAncestors() {
 		assert.Equal(t, expectedIdsMap, returnedIdsMap)
 	}
 }
+
+func (suite *AncestorsTestSuite) TestAncestorsContextCancelled() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+	}, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	returnedAncestors, err := AncestorsContext(ctx, g, graph.Node{Id: 3})
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, returnedAncestors)
+}
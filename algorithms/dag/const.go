@@ -4,7 +4,11 @@ import (
 	"errors"
 )
 
+// The number of nodes a search visits between checks for cancellation
+const cancellationInterval = 256
+
 var (
 	ErrCycle       = errors.New("Graph contains a cycle")
 	ErrNodeMissing = errors.New("Node not found in graph")
+	ErrUnreachable = errors.New("Unreachable node")
 )
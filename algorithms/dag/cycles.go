@@ -0,0 +1,251 @@
+package dag
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// A CycleError is returned when an operation requiring an acyclic graph encounters a cycle. It matches ErrCycle, so
+// callers which don't need the details can continue to use errors.Is(err, ErrCycle).
+type CycleError struct {
+	// Cycle holds the nodes of the cycle in order: there is an edge from each node to the next, and from the last node
+	// back to the first.
+	Cycle []graph.Node
+}
+
+func (e *CycleError) Error() string {
+	ids := make([]string, 0, len(e.Cycle)+1)
+	for _, n := range e.Cycle {
+		ids = append(ids, fmt.Sprint(n.ID()))
+	}
+	if len(e.Cycle) > 0 {
+		ids = append(ids, fmt.Sprint(e.Cycle[0].ID()))
+	}
+	return fmt.Sprintf("%s: %s", ErrCycle.Error(), strings.Join(ids, " -> "))
+}
+
+// Is reports whether target is ErrCycle, for use with errors.Is.
+func (e *CycleError) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// FindCycles returns every elementary cycle in the graph: that is, every cycle which visits no node more than once.
+// Each cycle is given as in CycleError, starting from its node with the lowest ID; cycles are ordered by that node.
+//
+// The number of elementary cycles can grow exponentially with the size of the graph, so this is only suitable for
+// graphs which are small or nearly acyclic.
+//
+// This is Johnson's algorithm [1]. The strongly connected components of the graph are found once, by Tarjan's algorithm
+// [2], and the search from each node is confined to its component. Each search takes O(V+E) time between finding one
+// cycle and the next, so the whole takes O((V+E)(V+C)) time for C cycles.
+//
+// [1] Johnson, D. B. Finding all the elementary circuits of a directed graph. SIAM J. Comput. 4(1), 77–84 (1975).
+// [2] Tarjan, R. E. Depth-first search and linear graph algorithms. SIAM J. Comput. 1(2), 146–160 (1972).
+func FindCycles(g graph.Graph) [][]graph.Node {
+	cycles, _ := FindCyclesContext(context.Background(), g)
+	return cycles
+}
+
+// FindCyclesContext is like FindCycles, but stops searching (returning ctx.Err()) once the context is done.
+func FindCyclesContext(ctx context.Context, g graph.Graph) ([][]graph.Node, error) {
+	nodes := g.NodeList()
+	sort.Sort(graph.NodesById(nodes))
+	index := make(map[graph.Node]int, len(nodes))
+	for i, n := range nodes {
+		index[n] = i
+	}
+	successors := make([][]int, len(nodes))
+	for i, n := range nodes {
+		for _, w := range g.Successors(n) {
+			successors[i] = append(successors[i], index[w])
+		}
+		sort.Ints(successors[i])
+	}
+
+	components, members := stronglyConnectedComponents(successors)
+	j := &johnson{
+		ctx:        ctx,
+		nodes:      nodes,
+		successors: successors,
+		components: components,
+		blocked:    make([]bool, len(nodes)),
+		blockedBy:  make([]map[int]bool, len(nodes)),
+	}
+	for s := range nodes {
+		// Every cycle whose lowest node is s lies within the component of s, among the nodes from s onwards
+		j.start = s
+		component := members[components[s]]
+		if len(component) == 1 && !j.selfLoop(s) {
+			continue
+		}
+		for _, v := range component {
+			j.blocked[v] = false
+			j.blockedBy[v] = nil
+		}
+		if _, err := j.circuit(s); err != nil {
+			return nil, err
+		}
+	}
+	return j.cycles, nil
+}
+
+// stronglyConnectedComponents returns the strongly connected component of each node, numbered from zero, along with
+// the members of each component.
+func stronglyConnectedComponents(successors [][]int) ([]int, [][]int) {
+	n := len(successors)
+	components := make([]int, n)
+	members := [][]int{}
+	order := make([]int, n) // The order in which nodes were first visited, from 1 (0 is unvisited)
+	low := make([]int, n)   // The earliest-visited node on the stack reachable from each node
+	onStack := make([]bool, n)
+	stack := []int{}
+	visited := 0
+
+	// The depth-first search is iterative, so deep graphs can't overflow the call stack. Each frame holds a node and
+	// the position of the next of its successors to visit
+	type frame struct{ v, next int }
+	for root := range successors {
+		if order[root] != 0 {
+			continue
+		}
+		frames := []frame{{v: root}}
+		visited++
+		order[root], low[root] = visited, visited
+		stack = append(stack, root)
+		onStack[root] = true
+		for len(frames) > 0 {
+			f := &frames[len(frames)-1]
+			v := f.v
+			if f.next < len(successors[v]) {
+				w := successors[v][f.next]
+				f.next++
+				if order[w] == 0 {
+					visited++
+					order[w], low[w] = visited, visited
+					stack = append(stack, w)
+					onStack[w] = true
+					frames = append(frames, frame{v: w})
+				} else if onStack[w] && order[w] < low[v] {
+					low[v] = order[w]
+				}
+				continue
+			}
+
+			frames = frames[:len(frames)-1]
+			if len(frames) > 0 {
+				if parent := frames[len(frames)-1].v; low[v] < low[parent] {
+					low[parent] = low[v]
+				}
+			}
+			if low[v] == order[v] { // v is the first-visited node of its component: pop the whole component
+				component := []int{}
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					components[w] = len(members)
+					component = append(component, w)
+					if w == v {
+						break
+					}
+				}
+				sort.Ints(component)
+				members = append(members, component)
+			}
+		}
+	}
+	return components, members
+}
+
+// johnson holds the state of a search by Johnson's algorithm. Nodes are referred to by their index in nodes.
+type johnson struct {
+	ctx        context.Context
+	nodes      []graph.Node
+	successors [][]int
+	components []int // The strongly connected component of each node
+	start      int
+	blocked    []bool
+	blockedBy  []map[int]bool // The nodes to unblock when each node is unblocked
+	stack      []int
+	cycles     [][]graph.Node
+	steps      int
+}
+
+// within returns whether v may be on a cycle through the start node: that is, whether it is in the same component, and
+// comes no earlier.
+func (j *johnson) within(v int) bool {
+	return v >= j.start && j.components[v] == j.components[j.start]
+}
+
+func (j *johnson) selfLoop(v int) bool {
+	for _, w := range j.successors[v] {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
+
+// circuit extends the current path from v, recording every cycle back to the start node. It returns whether any cycle
+// was found.
+func (j *johnson) circuit(v int) (bool, error) {
+	if j.steps%cancellationInterval == 0 {
+		if err := j.ctx.Err(); err != nil {
+			return false, err
+		}
+	}
+	j.steps++
+
+	found := false
+	j.stack = append(j.stack, v)
+	j.blocked[v] = true
+	for _, w := range j.successors[v] {
+		if !j.within(w) {
+			continue
+		}
+		if w == j.start {
+			cycle := make([]graph.Node, len(j.stack))
+			for i, u := range j.stack {
+				cycle[i] = j.nodes[u]
+			}
+			j.cycles = append(j.cycles, cycle)
+			found = true
+		} else if !j.blocked[w] {
+			f, err := j.circuit(w)
+			if err != nil {
+				return false, err
+			}
+			found = found || f
+		}
+	}
+
+	if found {
+		j.unblock(v)
+	} else {
+		// v stays blocked until one of its successors is unblocked, since only then might a new cycle pass through it
+		for _, w := range j.successors[v] {
+			if j.within(w) {
+				if j.blockedBy[w] == nil {
+					j.blockedBy[w] = map[int]bool{}
+				}
+				j.blockedBy[w][v] = true
+			}
+		}
+	}
+	j.stack = j.stack[:len(j.stack)-1]
+	return found, nil
+}
+
+func (j *johnson) unblock(v int) {
+	j.blocked[v] = false
+	for w := range j.blockedBy[v] {
+		delete(j.blockedBy[v], w)
+		if j.blocked[w] {
+			j.unblock(w)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package dag
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestCycles(t *testing.T) {
+	suite.Run(t, new(CyclesTestSuite))
+}
+
+type CyclesTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CyclesTestSuite) generateGraph(nodes []nodePrototype) graph.Graph {
+	g := graph.NewGraph()
+
+	for _, n := range nodes {
+		g.AddDirectedEdge(&graph.Edge{
+			H: graph.Node{Id: n.srcId},
+			T: graph.Node{Id: n.targetId},
+		})
+	}
+
+	return g
+}
+
+func (suite *CyclesTestSuite) ids(cycles [][]graph.Node) [][]int {
+	result := make([][]int, len(cycles))
+	for i, cycle := range cycles {
+		result[i] = make([]int, len(cycle))
+		for j, n := range cycle {
+			result[i][j] = n.ID()
+		}
+	}
+	return result
+}
+
+// countCycles counts the elementary cycles in g by brute force, extending every simple path from each node through
+// nodes with higher IDs only
+func (suite *CyclesTestSuite) countCycles(g graph.Graph) int {
+	count := 0
+	var extend func(start, v graph.Node, onPath map[int]bool)
+	extend = func(start, v graph.Node, onPath map[int]bool) {
+		for _, w := range g.Successors(v) {
+			if w.ID() == start.ID() {
+				count++
+			} else if w.ID() > start.ID() && !onPath[w.ID()] {
+				onPath[w.ID()] = true
+				extend(start, w, onPath)
+				delete(onPath, w.ID())
+			}
+		}
+	}
+	for _, n := range g.NodeList() {
+		extend(n, n, map[int]bool{n.ID(): true})
+	}
+	return count
+}
+
+func (suite *CyclesTestSuite) TestCycleError() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+		{3, 4},
+		{4, 2},
+	})
+
+	_, err := TopologicalSort(g)
+	assert.True(t, errors.Is(err, ErrCycle))
+	var cycleErr *CycleError
+	if assert.True(t, errors.As(err, &cycleErr)) {
+		assert.Len(t, cycleErr.Cycle, 3)
+		// The cycle may start from any of its nodes, but must follow the edges
+		for i, n := range cycleErr.Cycle {
+			next := cycleErr.Cycle[(i+1)%len(cycleErr.Cycle)]
+			assert.NotNil(t, g.EdgeTo(n, next))
+		}
+	}
+	assert.Contains(t, err.Error(), ErrCycle.Error())
+}
+
+func (suite *CyclesTestSuite) TestCycleErrorMessage() {
+	t := suite.T()
+	err := &CycleError{Cycle: []graph.Node{{Id: 2}, {Id: 3}, {Id: 4}}}
+	assert.Equal(t, "Graph contains a cycle: 2 -> 3 -> 4 -> 2", err.Error())
+	assert.True(t, errors.Is(err, ErrCycle))
+	assert.False(t, errors.Is(err, ErrNodeMissing))
+}
+
+func (suite *CyclesTestSuite) TestFindCycles() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+		{3, 1},
+		{3, 4},
+		{4, 2},
+		{4, 5},
+		{5, 6},
+	})
+
+	cycles := FindCycles(g)
+	assert.Equal(t, [][]int{
+		{1, 2, 3},
+		{2, 3, 4},
+	}, suite.ids(cycles))
+}
+
+func (suite *CyclesTestSuite) TestFindCyclesAcyclic() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+		{1, 3},
+		{3, 4},
+	})
+
+	assert.Empty(t, FindCycles(g))
+}
+
+func (suite *CyclesTestSuite) TestFindCyclesComplete() {
+	t := suite.T()
+	// A complete digraph on n nodes has sum over k=2..n of C(n, k)(k-1)! elementary cycles: 84 for n = 5
+	nodes := []nodePrototype{}
+	for i := 1; i <= 5; i++ {
+		for j := 1; j <= 5; j++ {
+			if i != j {
+				nodes = append(nodes, nodePrototype{i, j})
+			}
+		}
+	}
+	g := suite.generateGraph(nodes)
+
+	cycles := FindCycles(g)
+	assert.Len(t, cycles, 84)
+	for _, cycle := range cycles {
+		seen := map[int]bool{}
+		for i, n := range cycle {
+			assert.False(t, seen[n.ID()])
+			seen[n.ID()] = true
+			assert.True(t, n.ID() >= cycle[0].ID())
+			assert.NotNil(t, g.EdgeTo(n, cycle[(i+1)%len(cycle)]))
+		}
+	}
+}
+
+func (suite *CyclesTestSuite) TestFindCyclesRandom() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(21))
+	for round := 0; round < 50; round++ {
+		nodes := []nodePrototype{}
+		for i := 1; i <= 8; i++ {
+			for j := 1; j <= 8; j++ {
+				if i != j && rng.Float64() < 0.25 {
+					nodes = append(nodes, nodePrototype{i, j})
+				}
+			}
+		}
+		g := suite.generateGraph(nodes)
+
+		assert.Len(t, FindCycles(g), suite.countCycles(g))
+	}
+}
+
+func (suite *CyclesTestSuite) TestStronglyConnectedComponents() {
+	t := suite.T()
+	// Two cycles joined one way (0 -> 1 -> 2 -> 0, and 3 <-> 4), a lone node with a loop (5), and one without (6)
+	components, members := stronglyConnectedComponents([][]int{
+		{1},
+		{2},
+		{0, 3},
+		{4},
+		{3, 6},
+		{5},
+		{},
+	})
+	assert.Len(t, members, 4)
+	for _, component := range [][]int{{0, 1, 2}, {3, 4}, {5}, {6}} {
+		assert.Equal(t, component, members[components[component[0]]])
+		for _, v := range component {
+			assert.Equal(t, components[component[0]], components[v])
+		}
+	}
+
+	// A long chain would overflow a recursive search
+	chain := make([][]int, 100000)
+	for i := range chain[:len(chain)-1] {
+		chain[i] = []int{i + 1}
+	}
+	chain[len(chain)-1] = []int{0}
+	_, members = stronglyConnectedComponents(chain)
+	assert.Len(t, members, 1)
+}
+
+func (suite *CyclesTestSuite) TestFindCyclesContextCancelled() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 1},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cycles, err := FindCyclesContext(ctx, g)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, cycles)
+}
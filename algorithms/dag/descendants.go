@@ -1,10 +1,17 @@
 package dag
 
 import (
+	"context"
+
 	"github.com/obeattie/vrp/graph"
 )
 
 func Descendants(g graph.Graph, origin graph.Node) ([]graph.Node, error) {
+	return DescendantsContext(context.Background(), g, origin)
+}
+
+// DescendantsContext is like Descendants, but stops searching (returning ctx.Err()) once the context is done.
+func DescendantsContext(ctx context.Context, g graph.Graph, origin graph.Node) ([]graph.Node, error) {
 	if !g.NodeExists(origin) {
 		return nil, ErrNodeMissing
 	}
@@ -12,7 +19,13 @@ func Descendants(g graph.Graph, origin graph.Node) ([]graph.Node, error) {
 	var n graph.Node
 	resultSet := make(map[int]graph.Node, 10)
 	toVisit := []graph.Node{origin}
-	for len(toVisit) > 0 {
+	for i := 0; len(toVisit) > 0; i++ {
+		if i%cancellationInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
 		n, toVisit = toVisit[0], toVisit[1:]
 		if _, ok := resultSet[n.ID()]; ok { // Already visited
 			continue
@@ -0,0 +1,191 @@
+package dag
+
+import (
+	"sort"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// An IncrementalDAG wraps a graph to keep it acyclic as edges are added one at a time, maintaining a topological order
+// of its nodes as it goes. Adding an edge which would create a cycle fails, leaving the graph unchanged.
+//
+// Once wrapped, the graph must only be modified through the IncrementalDAG. An IncrementalDAG is not safe for
+// concurrent use.
+//
+// This is the dynamic topological sort algorithm of Pearce and Kelly [1]. Adding an edge which agrees with the current
+// order takes constant time; otherwise, only the nodes between the edge's endpoints in the order are searched and
+// reordered, rather than the whole graph being sorted again.
+//
+// [1] Pearce, D. J. & Kelly, P. H. J. A dynamic topological sort algorithm for directed acyclic graphs. J. Exp.
+// Algorithmics 11 (2006).
+type IncrementalDAG struct {
+	g    graph.Graph
+	ord  map[graph.Node]int // The position of each node in the topological order. Positions may have gaps.
+	next int                // The position of the next node to be added
+}
+
+// NewIncrementalDAG wraps the given graph. If it already contains cycles, a *CycleError is returned.
+func NewIncrementalDAG(g graph.Graph) (*IncrementalDAG, error) {
+	order, err := TopologicalSort(g)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &IncrementalDAG{
+		g:    g,
+		ord:  make(map[graph.Node]int, len(order)),
+		next: len(order),
+	}
+	for i, n := range order {
+		d.ord[n] = i
+	}
+	return d, nil
+}
+
+// Graph returns the wrapped graph, which must not be modified directly.
+func (d *IncrementalDAG) Graph() graph.Graph {
+	return d.g
+}
+
+// AddNode adds a node to the graph (if it isn't already present), placing it last in the topological order.
+func (d *IncrementalDAG) AddNode(n graph.Node) {
+	if _, ok := d.ord[n]; ok {
+		return
+	}
+	d.g.AddNode(n)
+	d.ord[n] = d.next
+	d.next++
+}
+
+// AddEdge adds a directed edge to the graph, adding its nodes if necessary. If the edge would create a cycle, it is not
+// added and a *CycleError is returned, holding the cycle it would have created.
+func (d *IncrementalDAG) AddEdge(e *graph.Edge) error {
+	if e.H == e.T {
+		return &CycleError{Cycle: []graph.Node{e.H}}
+	}
+	d.AddNode(e.H)
+	d.AddNode(e.T)
+
+	lower, upper := d.ord[e.T], d.ord[e.H]
+	if lower < upper { // The edge contradicts the current order
+		forward, cycle := d.forward(e.T, e.H, upper)
+		if cycle != nil {
+			return &CycleError{Cycle: cycle}
+		}
+		backward := d.backward(e.H, lower)
+		d.reorder(backward, forward)
+	}
+	d.g.AddDirectedEdge(e)
+	return nil
+}
+
+// RemoveEdge removes a directed edge from the graph. Removing an edge never invalidates the topological order.
+func (d *IncrementalDAG) RemoveEdge(e *graph.Edge) {
+	d.g.RemoveDirectedEdge(e)
+}
+
+// Order returns the nodes of the graph in the current topological order.
+func (d *IncrementalDAG) Order() []graph.Node {
+	nodes := make([]graph.Node, 0, len(d.ord))
+	for n := range d.ord {
+		nodes = append(nodes, n)
+	}
+	sort.Sort(nodesByOrder{nodes, d.ord})
+	return nodes
+}
+
+// Before returns whether a comes before b in the current topological order. This is necessary, but not sufficient, for
+// b to be reachable from a.
+func (d *IncrementalDAG) Before(a, b graph.Node) bool {
+	i, ok := d.ord[a]
+	j, ok2 := d.ord[b]
+	return ok && ok2 && i < j
+}
+
+// forward returns the nodes reachable from start which are no later than upper in the order. If target is among them,
+// it returns the path from start to target instead, which the new edge from target to start would close into a cycle.
+func (d *IncrementalDAG) forward(start, target graph.Node, upper int) ([]graph.Node, []graph.Node) {
+	parents := map[graph.Node]graph.Node{}
+	visited := map[graph.Node]bool{start: true}
+	result := []graph.Node{start}
+	toVisit := []graph.Node{start}
+	for len(toVisit) > 0 {
+		n := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		for _, w := range d.g.Successors(n) {
+			if visited[w] || d.ord[w] > upper {
+				continue
+			}
+			visited[w] = true
+			parents[w] = n
+			if w == target {
+				cycle := []graph.Node{w}
+				for u := w; u != start; {
+					u = parents[u]
+					cycle = append(cycle, u)
+				}
+				for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+				return nil, cycle
+			}
+			result = append(result, w)
+			toVisit = append(toVisit, w)
+		}
+	}
+	return result, nil
+}
+
+// backward returns the nodes which can reach start and are no earlier than lower in the order.
+func (d *IncrementalDAG) backward(start graph.Node, lower int) []graph.Node {
+	visited := map[graph.Node]bool{start: true}
+	result := []graph.Node{start}
+	toVisit := []graph.Node{start}
+	for len(toVisit) > 0 {
+		n := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		for _, w := range d.g.Predecessors(n) {
+			if visited[w] || d.ord[w] < lower {
+				continue
+			}
+			visited[w] = true
+			result = append(result, w)
+			toVisit = append(toVisit, w)
+		}
+	}
+	return result
+}
+
+// reorder moves the backward nodes (which must come before the new edge's tail) ahead of the forward nodes (which must
+// come after its head), reusing the positions they already occupy. Each group keeps its relative order.
+func (d *IncrementalDAG) reorder(backward, forward []graph.Node) {
+	sort.Sort(nodesByOrder{backward, d.ord})
+	sort.Sort(nodesByOrder{forward, d.ord})
+	nodes := append(backward, forward...)
+	positions := make([]int, len(nodes))
+	for i, n := range nodes {
+		positions[i] = d.ord[n]
+	}
+	sort.Ints(positions)
+	for i, n := range nodes {
+		d.ord[n] = positions[i]
+	}
+}
+
+// nodesByOrder sorts nodes by their position in a topological order
+type nodesByOrder struct {
+	nodes []graph.Node
+	ord   map[graph.Node]int
+}
+
+func (n nodesByOrder) Len() int {
+	return len(n.nodes)
+}
+
+func (n nodesByOrder) Less(i, j int) bool {
+	return n.ord[n.nodes[i]] < n.ord[n.nodes[j]]
+}
+
+func (n nodesByOrder) Swap(i, j int) {
+	n.nodes[i], n.nodes[j] = n.nodes[j], n.nodes[i]
+}
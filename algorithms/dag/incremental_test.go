@@ -0,0 +1,157 @@
+package dag
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestIncrementalDAG(t *testing.T) {
+	suite.Run(t, new(IncrementalDAGTestSuite))
+}
+
+type IncrementalDAGTestSuite struct {
+	suite.Suite
+}
+
+func (suite *IncrementalDAGTestSuite) edge(srcId, targetId int) *graph.Edge {
+	return &graph.Edge{
+		H: graph.Node{Id: srcId},
+		T: graph.Node{Id: targetId},
+	}
+}
+
+// assertOrdered asserts that every edge of the graph agrees with the DAG's topological order
+func (suite *IncrementalDAGTestSuite) assertOrdered(d *IncrementalDAG) {
+	t := suite.T()
+	order := d.Order()
+	position := make(map[graph.Node]int, len(order))
+	for i, n := range order {
+		position[n] = i
+	}
+	g := d.Graph()
+	assert.Len(t, order, len(g.NodeList()))
+	for _, n := range g.NodeList() {
+		for _, w := range g.Successors(n) {
+			assert.True(t, position[n] < position[w], "%d -> %d", n.ID(), w.ID())
+			assert.True(t, d.Before(n, w))
+		}
+	}
+}
+
+func (suite *IncrementalDAGTestSuite) TestAddEdge() {
+	t := suite.T()
+	d, err := NewIncrementalDAG(graph.NewGraph())
+	assert.NoError(t, err)
+
+	// Added in an order which contradicts the order the nodes were first seen in
+	assert.NoError(t, d.AddEdge(suite.edge(3, 4)))
+	assert.NoError(t, d.AddEdge(suite.edge(2, 3)))
+	assert.NoError(t, d.AddEdge(suite.edge(1, 2)))
+	assert.NoError(t, d.AddEdge(suite.edge(4, 5)))
+	suite.assertOrdered(d)
+
+	ids := []int{}
+	for _, n := range d.Order() {
+		ids = append(ids, n.ID())
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+}
+
+func (suite *IncrementalDAGTestSuite) TestAddEdgeCycle() {
+	t := suite.T()
+	d, err := NewIncrementalDAG(graph.NewGraph())
+	assert.NoError(t, err)
+	assert.NoError(t, d.AddEdge(suite.edge(1, 2)))
+	assert.NoError(t, d.AddEdge(suite.edge(2, 3)))
+	assert.NoError(t, d.AddEdge(suite.edge(3, 4)))
+
+	err = d.AddEdge(suite.edge(4, 2))
+	assert.True(t, errors.Is(err, ErrCycle))
+	var cycleErr *CycleError
+	if assert.True(t, errors.As(err, &cycleErr)) {
+		ids := []int{}
+		for _, n := range cycleErr.Cycle {
+			ids = append(ids, n.ID())
+		}
+		assert.Equal(t, []int{2, 3, 4}, ids)
+	}
+	// The graph is unchanged
+	assert.Nil(t, d.Graph().EdgeTo(graph.Node{Id: 4}, graph.Node{Id: 2}))
+	suite.assertOrdered(d)
+
+	err = d.AddEdge(suite.edge(5, 5))
+	assert.True(t, errors.Is(err, ErrCycle))
+	assert.False(t, d.Graph().NodeExists(graph.Node{Id: 5}))
+}
+
+func (suite *IncrementalDAGTestSuite) TestRemoveEdge() {
+	t := suite.T()
+	d, err := NewIncrementalDAG(graph.NewGraph())
+	assert.NoError(t, err)
+	assert.NoError(t, d.AddEdge(suite.edge(1, 2)))
+	assert.Error(t, d.AddEdge(suite.edge(2, 1)))
+
+	d.RemoveEdge(suite.edge(1, 2))
+	assert.NoError(t, d.AddEdge(suite.edge(2, 1)))
+	suite.assertOrdered(d)
+}
+
+func (suite *IncrementalDAGTestSuite) TestNewIncrementalDAG() {
+	t := suite.T()
+	g := graph.NewGraph()
+	g.AddDirectedEdge(suite.edge(1, 2))
+	g.AddDirectedEdge(suite.edge(2, 3))
+
+	d, err := NewIncrementalDAG(g)
+	assert.NoError(t, err)
+	suite.assertOrdered(d)
+	assert.Error(t, d.AddEdge(suite.edge(3, 1)))
+
+	g.AddDirectedEdge(suite.edge(3, 1))
+	d, err = NewIncrementalDAG(g)
+	assert.Nil(t, d)
+	assert.True(t, errors.Is(err, ErrCycle))
+}
+
+func (suite *IncrementalDAGTestSuite) TestAddEdgeRandom() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(25))
+	for round := 0; round < 10; round++ {
+		d, err := NewIncrementalDAG(graph.NewGraph())
+		assert.NoError(t, err)
+		for i := 0; i < 150; i++ {
+			e := suite.edge(rng.Intn(30)+1, rng.Intn(30)+1)
+
+			// The edge creates a cycle exactly when its head is already reachable from its tail
+			createsCycle := e.H == e.T
+			if d.Graph().NodeExists(e.T) {
+				descendants, _ := Descendants(d.Graph(), e.T)
+				for _, n := range descendants {
+					createsCycle = createsCycle || n == e.H
+				}
+			}
+
+			err := d.AddEdge(e)
+			if !createsCycle {
+				assert.NoError(t, err)
+				continue
+			}
+			var cycleErr *CycleError
+			if assert.True(t, errors.As(err, &cycleErr)) {
+				cycle := cycleErr.Cycle
+				for j := 0; j < len(cycle)-1; j++ {
+					assert.NotNil(t, d.Graph().EdgeTo(cycle[j], cycle[j+1]))
+				}
+				assert.Equal(t, e.T, cycle[0])
+				assert.Equal(t, e.H, cycle[len(cycle)-1])
+			}
+		}
+		suite.assertOrdered(d)
+	}
+}
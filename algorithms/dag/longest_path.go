@@ -0,0 +1,116 @@
+package dag
+
+import (
+	"math"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/graph"
+)
+
+// LongestPath returns the most costly path anywhere in the graph. Edge costs may be negative; a path never starts with
+// or ends with a negative-cost edge, since dropping it would give a more costly path. For a graph without edges, the
+// path is a single node (or is empty, for an empty graph).
+//
+// The longest path problem is NP-hard in general, but is solvable in linear time on a DAG by relaxing edges in
+// topological order. If the given Graph contains cycles, a *CycleError is returned.
+func LongestPath(g graph.Graph) (shortestpaths.Path, error) {
+	order, err := TopologicalSort(g)
+	if err != nil {
+		return shortestpaths.Path{}, err
+	}
+	if len(order) == 0 {
+		return shortestpaths.Path{}, nil
+	}
+
+	earliest, parents := earliestStarts(g, order)
+	last := order[0]
+	for _, n := range order {
+		if earliest[n] > earliest[last] {
+			last = n
+		}
+	}
+	return pathTo(g, last, parents), nil
+}
+
+// earliestStarts returns the cost of the most costly path ending at each node (which is never negative, since a path
+// may start at any node), along with the parent of each node on that path.
+func earliestStarts(g graph.Graph, order []graph.Node) (map[graph.Node]float64, map[graph.Node]graph.Node) {
+	earliest := make(map[graph.Node]float64, len(order))
+	parents := make(map[graph.Node]graph.Node, len(order))
+	for _, n := range order {
+		for _, w := range g.Successors(n) {
+			if cost := earliest[n] + g.EdgeTo(n, w).Cost; cost > earliest[w] {
+				earliest[w] = cost
+				parents[w] = n
+			}
+		}
+	}
+	return earliest, parents
+}
+
+// NodeTimes are the scheduled times of a node in a Schedule.
+type NodeTimes struct {
+	// EarliestStart is the earliest time at which the node can start, given that every predecessor must have started
+	// at least the cost of the edge between them earlier.
+	EarliestStart float64
+	// LatestStart is the latest time at which the node can start without delaying the completion of the schedule.
+	LatestStart float64
+	// Slack is the amount by which the node's start can be delayed without delaying the completion of the schedule.
+	// Nodes on a critical path have no slack.
+	Slack float64
+}
+
+// A Schedule is the result of a critical path analysis of a graph.
+type Schedule struct {
+	// Makespan is the time at which the last node can start: the cost of the critical path.
+	Makespan float64
+	// CriticalPath is a longest path through the graph. Delaying any node on it delays the whole schedule.
+	CriticalPath shortestpaths.Path
+	// Times holds the scheduled times of each node.
+	Times map[graph.Node]NodeTimes
+}
+
+// CriticalPath schedules the nodes of the graph using the critical path method. Each node is an event (such as the
+// start of a task), and each edge's cost is the minimum time which must elapse between the start of its head and the
+// start of its tail: typically, the duration of the task at its head. Every node starts at or after time zero.
+//
+// If the given Graph contains cycles, a *CycleError is returned.
+func CriticalPath(g graph.Graph) (*Schedule, error) {
+	order, err := TopologicalSort(g)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schedule{
+		Times: make(map[graph.Node]NodeTimes, len(order)),
+	}
+	if len(order) == 0 {
+		return s, nil
+	}
+
+	earliest, parents := earliestStarts(g, order)
+	last := order[0]
+	for _, n := range order {
+		if earliest[n] > earliest[last] {
+			last = n
+		}
+	}
+	s.Makespan = earliest[last]
+	s.CriticalPath = pathTo(g, last, parents)
+
+	// Work backwards from the end of the schedule to find how late each node can start
+	latest := make(map[graph.Node]float64, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		n := order[i]
+		latest[n] = s.Makespan
+		for _, w := range g.Successors(n) {
+			latest[n] = math.Min(latest[n], latest[w]-g.EdgeTo(n, w).Cost)
+		}
+		s.Times[n] = NodeTimes{
+			EarliestStart: earliest[n],
+			LatestStart:   latest[n],
+			Slack:         latest[n] - earliest[n],
+		}
+	}
+	return s, nil
+}
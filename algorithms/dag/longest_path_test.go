@@ -0,0 +1,151 @@
+package dag
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/graph"
+)
+
+type costedEdgePrototype struct {
+	srcId, targetId int
+	cost            float64
+}
+
+func generateCostedGraph(edges []costedEdgePrototype) graph.Graph {
+	g := graph.NewGraph()
+
+	for _, e := range edges {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: e.srcId},
+			T:    graph.Node{Id: e.targetId},
+			Cost: e.cost,
+		})
+	}
+
+	return g
+}
+
+func pathIds(p shortestpaths.Path) []int {
+	result := make([]int, len(p.Nodes))
+	for i, n := range p.Nodes {
+		result[i] = n.ID()
+	}
+	return result
+}
+
+func TestLongestPath(t *testing.T) {
+	suite.Run(t, new(LongestPathTestSuite))
+}
+
+type LongestPathTestSuite struct {
+	suite.Suite
+}
+
+// A depot loading plan: each edge's cost is the duration of the task at its head
+func (suite *LongestPathTestSuite) schedule() graph.Graph {
+	return generateCostedGraph([]costedEdgePrototype{
+		{1, 2, 3},
+		{1, 3, 2},
+		{2, 4, 4},
+		{3, 4, 1},
+		{4, 5, 2},
+	})
+}
+
+func (suite *LongestPathTestSuite) TestLongestPath() {
+	t := suite.T()
+	g := suite.schedule()
+
+	path, err := LongestPath(g)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 4, 5}, pathIds(path))
+	assert.Len(t, path.Edges, 3)
+	assert.Equal(t, 9.0, path.Cost)
+}
+
+func (suite *LongestPathTestSuite) TestLongestPathNegativeCosts() {
+	t := suite.T()
+	g := generateCostedGraph([]costedEdgePrototype{
+		{1, 2, -5},
+		{2, 3, 10},
+		{3, 4, -1},
+	})
+
+	path, err := LongestPath(g)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, pathIds(path))
+	assert.Equal(t, 10.0, path.Cost)
+}
+
+func (suite *LongestPathTestSuite) TestLongestPathEmpty() {
+	t := suite.T()
+
+	path, err := LongestPath(graph.NewGraph())
+	assert.NoError(t, err)
+	assert.Empty(t, path.Nodes)
+	assert.Equal(t, 0.0, path.Cost)
+}
+
+func (suite *LongestPathTestSuite) TestLongestPathCycles() {
+	t := suite.T()
+	g := generateCostedGraph([]costedEdgePrototype{
+		{1, 2, 1},
+		{2, 1, 1},
+	})
+
+	_, err := LongestPath(g)
+	assert.True(t, errors.Is(err, ErrCycle))
+}
+
+func (suite *LongestPathTestSuite) TestCriticalPath() {
+	t := suite.T()
+	g := suite.schedule()
+
+	s, err := CriticalPath(g)
+	assert.NoError(t, err)
+	assert.Equal(t, 9.0, s.Makespan)
+	assert.Equal(t, []int{1, 2, 4, 5}, pathIds(s.CriticalPath))
+	assert.Equal(t, map[graph.Node]NodeTimes{
+		{Id: 1}: {EarliestStart: 0, LatestStart: 0, Slack: 0},
+		{Id: 2}: {EarliestStart: 3, LatestStart: 3, Slack: 0},
+		{Id: 3}: {EarliestStart: 2, LatestStart: 6, Slack: 4},
+		{Id: 4}: {EarliestStart: 7, LatestStart: 7, Slack: 0},
+		{Id: 5}: {EarliestStart: 9, LatestStart: 9, Slack: 0},
+	}, s.Times)
+}
+
+func (suite *LongestPathTestSuite) TestCriticalPathParallelChains() {
+	t := suite.T()
+	// Two independent chains finish at different times; the shorter one has slack
+	g := generateCostedGraph([]costedEdgePrototype{
+		{1, 2, 5},
+		{3, 4, 2},
+	})
+
+	s, err := CriticalPath(g)
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, s.Makespan)
+	assert.Equal(t, []int{1, 2}, pathIds(s.CriticalPath))
+	assert.Equal(t, 3.0, s.Times[graph.Node{Id: 3}].Slack)
+	assert.Equal(t, 3.0, s.Times[graph.Node{Id: 4}].Slack)
+	assert.Equal(t, 5.0, s.Times[graph.Node{Id: 4}].LatestStart)
+}
+
+func (suite *LongestPathTestSuite) TestCriticalPathCycles() {
+	t := suite.T()
+	g := generateCostedGraph([]costedEdgePrototype{
+		{1, 2, 1},
+		{2, 3, 1},
+		{3, 1, 1},
+	})
+
+	s, err := CriticalPath(g)
+	assert.Nil(t, s)
+	var cycleErr *CycleError
+	assert.True(t, errors.As(err, &cycleErr))
+}
@@ -0,0 +1,29 @@
+package dag
+
+import (
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/graph"
+)
+
+// pathTo returns the path ending at target which is recorded by the given parents (the predecessor of each node on
+// its path; the first node has none).
+func pathTo(g graph.Graph, target graph.Node, parents map[graph.Node]graph.Node) shortestpaths.Path {
+	nodes := []graph.Node{target}
+	for n, ok := parents[target]; ok; n, ok = parents[n] {
+		nodes = append(nodes, n)
+	}
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+
+	p := shortestpaths.Path{
+		Nodes: nodes,
+		Edges: make([]*graph.Edge, 0, len(nodes)-1),
+	}
+	for i := 1; i < len(nodes); i++ {
+		edge := g.EdgeTo(nodes[i-1], nodes[i])
+		p.Edges = append(p.Edges, edge)
+		p.Cost += edge.Cost
+	}
+	return p
+}
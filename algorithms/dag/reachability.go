@@ -0,0 +1,76 @@
+package dag
+
+import (
+	"github.com/obeattie/vrp/graph"
+)
+
+// Reachability is an index of the transitive closure of a DAG, answering whether one node can reach another in
+// constant time. It takes O(V²/64) words of memory, so it suits graphs of up to a few tens of thousands of nodes.
+//
+// A Reachability is only valid for the graph it was built from, and only for as long as it is unchanged.
+type Reachability struct {
+	index       map[graph.Node]int // Position of each node in topological order
+	descendants []bitset           // The nodes reachable from each node, by index
+}
+
+// NewReachability builds the reachability index of the graph. Each node's descendants are the union of those of its
+// successors, so computing them in reverse topological order takes O(V·E/64) time.
+//
+// If the given Graph contains cycles, a *CycleError is returned.
+func NewReachability(g graph.Graph) (*Reachability, error) {
+	order, err := TopologicalSort(g)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reachability{
+		index:       make(map[graph.Node]int, len(order)),
+		descendants: make([]bitset, len(order)),
+	}
+	for i, n := range order {
+		r.index[n] = i
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		d := newBitset(len(order))
+		for _, w := range g.Successors(order[i]) {
+			j := r.index[w]
+			d.set(j)
+			d.union(r.descendants[j])
+		}
+		r.descendants[i] = d
+	}
+	return r, nil
+}
+
+// Reaches returns whether there is a path of at least one edge from a to b: that is, whether b is a descendant of a,
+// and so must come after it in any topological order. It is false if either node was not in the graph.
+func (r *Reachability) Reaches(a, b graph.Node) bool {
+	i, ok := r.index[a]
+	if !ok {
+		return false
+	}
+	j, ok := r.index[b]
+	return ok && r.descendants[i].has(j)
+}
+
+// bitset is a fixed-size set of small non-negative integers.
+type bitset []uint64
+
+func newBitset(size int) bitset {
+	return make(bitset, (size+63)/64)
+}
+
+func (b bitset) set(i int) {
+	b[i/64] |= 1 << uint(i%64)
+}
+
+func (b bitset) has(i int) bool {
+	return b[i/64]&(1<<uint(i%64)) != 0
+}
+
+// union adds every member of other to b.
+func (b bitset) union(other bitset) {
+	for i, word := range other {
+		b[i] |= word
+	}
+}
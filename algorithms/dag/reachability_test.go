@@ -0,0 +1,111 @@
+package dag
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// randomDAG returns a graph on n nodes with each forward edge (from a lower ID to a higher one) present with
+// probability p
+func randomDAG(rng *rand.Rand, n int, p float64) graph.Graph {
+	g := graph.NewGraph()
+	for i := 1; i <= n; i++ {
+		g.AddNode(graph.Node{Id: i})
+	}
+	for i := 1; i <= n; i++ {
+		for j := i + 1; j <= n; j++ {
+			if rng.Float64() < p {
+				g.AddDirectedEdge(&graph.Edge{
+					H:    graph.Node{Id: i},
+					T:    graph.Node{Id: j},
+					Cost: float64(rng.Intn(10)),
+				})
+			}
+		}
+	}
+	return g
+}
+
+func TestReachability(t *testing.T) {
+	suite.Run(t, new(ReachabilityTestSuite))
+}
+
+type ReachabilityTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ReachabilityTestSuite) generateGraph(nodes []nodePrototype) graph.Graph {
+	g := graph.NewGraph()
+
+	for _, n := range nodes {
+		g.AddDirectedEdge(&graph.Edge{
+			H: graph.Node{Id: n.srcId},
+			T: graph.Node{Id: n.targetId},
+		})
+	}
+
+	return g
+}
+
+func (suite *ReachabilityTestSuite) TestReaches() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+		{1, 4},
+		{5, 4},
+	})
+
+	r, err := NewReachability(g)
+	assert.NoError(t, err)
+	assert.True(t, r.Reaches(graph.Node{Id: 1}, graph.Node{Id: 2}))
+	assert.True(t, r.Reaches(graph.Node{Id: 1}, graph.Node{Id: 3}))
+	assert.True(t, r.Reaches(graph.Node{Id: 1}, graph.Node{Id: 4}))
+	assert.True(t, r.Reaches(graph.Node{Id: 5}, graph.Node{Id: 4}))
+	assert.False(t, r.Reaches(graph.Node{Id: 3}, graph.Node{Id: 1}))
+	assert.False(t, r.Reaches(graph.Node{Id: 2}, graph.Node{Id: 4}))
+	assert.False(t, r.Reaches(graph.Node{Id: 1}, graph.Node{Id: 5}))
+	assert.False(t, r.Reaches(graph.Node{Id: 1}, graph.Node{Id: 1}))
+	assert.False(t, r.Reaches(graph.Node{Id: 1}, graph.Node{Id: 6}))
+	assert.False(t, r.Reaches(graph.Node{Id: 6}, graph.Node{Id: 1}))
+}
+
+func (suite *ReachabilityTestSuite) TestReachesRandom() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(24))
+	for round := 0; round < 10; round++ {
+		g := randomDAG(rng, 100, 0.03) // Enough nodes to span several words
+
+		r, err := NewReachability(g)
+		assert.NoError(t, err)
+		for _, a := range g.NodeList() {
+			descendants, err := Descendants(g, a)
+			assert.NoError(t, err)
+			expected := map[int]bool{}
+			for _, d := range descendants {
+				expected[d.ID()] = true
+			}
+			for _, b := range g.NodeList() {
+				assert.Equal(t, expected[b.ID()], r.Reaches(a, b), "%d -> %d", a.ID(), b.ID())
+			}
+		}
+	}
+}
+
+func (suite *ReachabilityTestSuite) TestReachabilityCycles() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 1},
+	})
+
+	r, err := NewReachability(g)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrCycle))
+}
@@ -0,0 +1,45 @@
+package dag
+
+import (
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/graph"
+)
+
+// ShortestPath returns the least costly path from source to target. Edge costs may be negative.
+//
+// Relaxing edges in topological order takes O(V+E) time, which is faster than Dijkstra's algorithm on a DAG and does
+// not require costs to be non-negative. If the given Graph contains cycles, a *CycleError is returned; if target is not
+// reachable from source, ErrUnreachable is.
+func ShortestPath(g graph.Graph, source, target graph.Node) (shortestpaths.Path, error) {
+	if !g.NodeExists(source) || !g.NodeExists(target) {
+		return shortestpaths.Path{}, ErrNodeMissing
+	}
+	order, err := TopologicalSort(g)
+	if err != nil {
+		return shortestpaths.Path{}, err
+	}
+
+	costs := map[graph.Node]float64{source: 0} // Only nodes reachable from source have a cost
+	parents := map[graph.Node]graph.Node{}
+	for _, n := range order {
+		if n == target {
+			break // Nothing after the target in the order can lead back to it
+		}
+		cost, ok := costs[n]
+		if !ok {
+			continue
+		}
+		for _, w := range g.Successors(n) {
+			through := cost + g.EdgeTo(n, w).Cost
+			if c, ok := costs[w]; !ok || through < c {
+				costs[w] = through
+				parents[w] = n
+			}
+		}
+	}
+
+	if _, ok := costs[target]; !ok {
+		return shortestpaths.Path{}, ErrUnreachable
+	}
+	return pathTo(g, target, parents), nil
+}
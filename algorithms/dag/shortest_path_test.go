@@ -0,0 +1,118 @@
+package dag
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestShortestPath(t *testing.T) {
+	suite.Run(t, new(ShortestPathTestSuite))
+}
+
+type ShortestPathTestSuite struct {
+	suite.Suite
+}
+
+// bruteForceCost returns the cost of the least costly path from source to target by enumerating every path
+func (suite *ShortestPathTestSuite) bruteForceCost(g graph.Graph, source, target graph.Node) float64 {
+	if source == target {
+		return 0
+	}
+	best := math.Inf(1)
+	for _, w := range g.Successors(source) {
+		best = math.Min(best, g.EdgeTo(source, w).Cost+suite.bruteForceCost(g, w, target))
+	}
+	return best
+}
+
+func (suite *ShortestPathTestSuite) TestShortestPath() {
+	t := suite.T()
+	g := generateCostedGraph([]costedEdgePrototype{
+		{1, 2, 4},
+		{1, 3, 2},
+		{3, 2, -3},
+		{2, 4, 1},
+		{3, 4, 5},
+	})
+
+	path, err := ShortestPath(g, graph.Node{Id: 1}, graph.Node{Id: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3, 2, 4}, pathIds(path))
+	assert.Len(t, path.Edges, 3)
+	assert.Equal(t, 0.0, path.Cost)
+}
+
+func (suite *ShortestPathTestSuite) TestShortestPathSameNode() {
+	t := suite.T()
+	g := generateCostedGraph([]costedEdgePrototype{
+		{1, 2, 1},
+	})
+
+	path, err := ShortestPath(g, graph.Node{Id: 1}, graph.Node{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, pathIds(path))
+	assert.Empty(t, path.Edges)
+}
+
+func (suite *ShortestPathTestSuite) TestShortestPathUnreachable() {
+	t := suite.T()
+	g := generateCostedGraph([]costedEdgePrototype{
+		{1, 2, 1},
+		{3, 2, 1},
+	})
+
+	_, err := ShortestPath(g, graph.Node{Id: 1}, graph.Node{Id: 3})
+	assert.Equal(t, ErrUnreachable, err)
+	_, err = ShortestPath(g, graph.Node{Id: 1}, graph.Node{Id: 4})
+	assert.Equal(t, ErrNodeMissing, err)
+}
+
+func (suite *ShortestPathTestSuite) TestShortestPathCycles() {
+	t := suite.T()
+	g := generateCostedGraph([]costedEdgePrototype{
+		{1, 2, 1},
+		{2, 3, 1},
+		{3, 2, 1},
+	})
+
+	_, err := ShortestPath(g, graph.Node{Id: 1}, graph.Node{Id: 3})
+	assert.True(t, errors.Is(err, ErrCycle))
+}
+
+func (suite *ShortestPathTestSuite) TestShortestPathRandom() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(23))
+	for round := 0; round < 20; round++ {
+		edges := []costedEdgePrototype{}
+		for i := 1; i <= 10; i++ {
+			for j := i + 1; j <= 10; j++ {
+				if rng.Float64() < 0.3 {
+					edges = append(edges, costedEdgePrototype{i, j, float64(rng.Intn(21) - 5)})
+				}
+			}
+		}
+		g := generateCostedGraph(edges)
+
+		for _, source := range g.NodeList() {
+			for _, target := range g.NodeList() {
+				expected := suite.bruteForceCost(g, source, target)
+				path, err := ShortestPath(g, source, target)
+				if math.IsInf(expected, 1) {
+					assert.Equal(t, ErrUnreachable, err)
+					continue
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, expected, path.Cost)
+				assert.Equal(t, source, path.Nodes[0])
+				assert.Equal(t, target, path.Nodes[len(path.Nodes)-1])
+			}
+		}
+	}
+}
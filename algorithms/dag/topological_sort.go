@@ -1,6 +1,8 @@
 package dag
 
 import (
+	"context"
+
 	"github.com/obeattie/vrp/graph"
 )
 
@@ -9,14 +11,20 @@ import (
 // A topological sort is a noninique permutation of the nodes such that an edge from u to v implies that u appears
 // before v in the topological sort order.
 //
-// If a topological sort is infeasible because the given Graph contains cycles, ErrCycle is returned.
+// If a topological sort is infeasible because the given Graph contains cycles, a *CycleError (matching ErrCycle) is
+// returned, holding one of the cycles.
 //
 // This algorithm is based on a description and proof in The Algorithm Design Manual [1].
 //
 // [1] Skiena, S. S. The Algorithm Design Manual  (Springer-Verlag, 1998).
 //     http://www.amazon.com/exec/obidos/ASIN/0387948600/ref=ase_thealgorithmrepo/
 func TopologicalSort(g graph.Graph) ([]graph.Node, error) {
-	order, err := TopologicalSortReverse(g)
+	return TopologicalSortContext(context.Background(), g)
+}
+
+// TopologicalSortContext is like TopologicalSort, but stops sorting (returning ctx.Err()) once the context is done.
+func TopologicalSortContext(ctx context.Context, g graph.Graph) ([]graph.Node, error) {
+	order, err := topologicalSortReverse(ctx, g)
 	if err != nil {
 		return order, err
 	}
@@ -29,35 +37,62 @@ func TopologicalSort(g graph.Graph) ([]graph.Node, error) {
 }
 
 // TopologicalSortReverse returns a postorder topological sort of the Nodes (ie. an array in the reverse order to that
-// returned by TopologicalSort).
+// returned by TopologicalSort). As with TopologicalSort, a *CycleError is returned if the Graph contains cycles.
 func TopologicalSortReverse(g graph.Graph) ([]graph.Node, error) {
+	return topologicalSortReverse(context.Background(), g)
+}
+
+func topologicalSortReverse(ctx context.Context, g graph.Graph) ([]graph.Node, error) {
+	type fringeEntry struct {
+		node, parent graph.Node // The parent is the node which added this entry to the fringe
+	}
+
 	nodesList := g.NodeList()
 	seen := make(map[graph.Node]bool)
 	order := make([]graph.Node, 0, len(nodesList))
 	explored := make(map[graph.Node]bool)
+	parents := make(map[graph.Node]graph.Node) // The node from which each node was first explored
 
 	for _, v := range nodesList {
 		if _, ok := explored[v]; ok { // Node has been explored already
 			continue
 		}
 
-		fringe := []graph.Node{v}
-		for len(fringe) > 0 {
-			w := fringe[len(fringe)-1]
+		fringe := []fringeEntry{{node: v}}
+		for i := 0; len(fringe) > 0; i++ {
+			if i%cancellationInterval == 0 {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+			}
+
+			entry := fringe[len(fringe)-1]
+			w := entry.node
 			if _, ok := explored[w]; ok { // Node has been explored already
 				fringe = fringe[:len(fringe)-1]
 				continue
 			}
-			seen[w] = true // Mark as seen
+			if !seen[w] {
+				seen[w] = true // Mark as seen
+				parents[w] = entry.parent
+			}
 
 			// Check successors for cycles and for new nodes
-			new_nodes := make([]graph.Node, 0)
+			new_nodes := make([]fringeEntry, 0)
 			for _, n := range g.Successors(w) {
 				if _, ok := explored[n]; !ok {
-					if _, ok = seen[n]; ok { // Cycle!
-						return nil, ErrCycle
+					if _, ok = seen[n]; ok { // Cycle! Nodes seen but not explored are w and its ancestors
+						cycle := []graph.Node{w}
+						for u := w; u != n; {
+							u = parents[u]
+							cycle = append(cycle, u)
+						}
+						for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+							cycle[i], cycle[j] = cycle[j], cycle[i]
+						}
+						return nil, &CycleError{Cycle: cycle}
 					}
-					new_nodes = append(new_nodes, n)
+					new_nodes = append(new_nodes, fringeEntry{node: n, parent: w})
 				}
 			}
 			if len(new_nodes) > 0 { // Add new_nodes to fringe
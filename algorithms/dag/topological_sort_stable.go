@@ -0,0 +1,183 @@
+package dag
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// TopologicalSortStable returns a list of Nodes in topological sort order, as TopologicalSort does, but
+// deterministically: whenever more than one node could come next, the least according to less is chosen. The result
+// is therefore the lexicographically smallest topological order. If less is nil, nodes are compared by ID.
+//
+// If the given Graph contains cycles, a *CycleError is returned.
+//
+// This is Kahn's algorithm [1] with a priority queue in place of a set, taking O(V log V + E) time.
+//
+// [1] Kahn, A. B. Topological sorting of large networks. Commun. ACM 5(11), 558–562 (1962).
+func TopologicalSortStable(g graph.Graph, less func(a, b graph.Node) bool) ([]graph.Node, error) {
+	return TopologicalSortStableContext(context.Background(), g, less)
+}
+
+// TopologicalSortStableContext is like TopologicalSortStable, but stops sorting (returning ctx.Err()) once the
+// context is done.
+func TopologicalSortStableContext(ctx context.Context, g graph.Graph, less func(a, b graph.Node) bool) ([]graph.Node, error) {
+	if less == nil {
+		less = lessById
+	}
+
+	nodesList := g.NodeList()
+	inDegree := make(map[graph.Node]int, len(nodesList))
+	ready := &nodeQueue{less: less}
+	for _, n := range nodesList {
+		inDegree[n] = len(g.Predecessors(n))
+		if inDegree[n] == 0 {
+			ready.nodes = append(ready.nodes, n)
+		}
+	}
+	heap.Init(ready)
+
+	order := make([]graph.Node, 0, len(nodesList))
+	for i := 0; ready.Len() > 0; i++ {
+		if i%cancellationInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		n := heap.Pop(ready).(graph.Node)
+		order = append(order, n)
+		for _, w := range g.Successors(n) {
+			if inDegree[w]--; inDegree[w] == 0 {
+				heap.Push(ready, w)
+			}
+		}
+	}
+
+	if len(order) < len(nodesList) {
+		return nil, &CycleError{Cycle: remainingCycle(g, inDegree)}
+	}
+	return order, nil
+}
+
+// remainingCycle returns a cycle among the nodes which Kahn's algorithm could not sort (those with a positive
+// remaining in-degree). Every such node has an unsorted predecessor, so walking backwards from any of them must
+// eventually revisit a node.
+func remainingCycle(g graph.Graph, inDegree map[graph.Node]int) []graph.Node {
+	var n graph.Node
+	for _, v := range g.NodeList() {
+		if inDegree[v] > 0 {
+			n = v
+			break
+		}
+	}
+
+	position := map[graph.Node]int{}
+	walk := []graph.Node{}
+	for {
+		if i, ok := position[n]; ok {
+			walk = walk[i:]
+			break
+		}
+		position[n] = len(walk)
+		walk = append(walk, n)
+		for _, p := range g.Predecessors(n) {
+			if inDegree[p] > 0 {
+				n = p
+				break
+			}
+		}
+	}
+
+	// The walk followed edges backwards
+	for i, j := 0, len(walk)-1; i < j; i, j = i+1, j-1 {
+		walk[i], walk[j] = walk[j], walk[i]
+	}
+	return walk
+}
+
+// AllTopologicalSorts calls fn with every topological sort order of the graph in turn, in lexicographic order of
+// node ID, until fn returns false. Each order is a new slice which fn may keep.
+//
+// A graph can have factorially many topological orders, so this is only suitable for small graphs. If the given Graph
+// contains cycles it has none, and a *CycleError is returned without fn being called.
+func AllTopologicalSorts(g graph.Graph, fn func(order []graph.Node) bool) error {
+	if _, err := TopologicalSortStable(g, nil); err != nil {
+		return err
+	}
+
+	nodesList := g.NodeList()
+	inDegree := make(map[graph.Node]int, len(nodesList))
+	for _, n := range nodesList {
+		inDegree[n] = len(g.Predecessors(n))
+	}
+	nodes := append([]graph.Node(nil), nodesList...)
+	sort.Sort(graph.NodesById(nodes))
+
+	order := make([]graph.Node, 0, len(nodes))
+	placed := make(map[graph.Node]bool, len(nodes))
+	// extend tries each node which could come next in turn, returning false once fn asks to stop
+	var extend func() bool
+	extend = func() bool {
+		if len(order) == len(nodes) {
+			return fn(append([]graph.Node(nil), order...))
+		}
+
+		for _, n := range nodes {
+			if placed[n] || inDegree[n] > 0 {
+				continue
+			}
+			placed[n] = true
+			order = append(order, n)
+			for _, w := range g.Successors(n) {
+				inDegree[w]--
+			}
+			more := extend()
+			for _, w := range g.Successors(n) {
+				inDegree[w]++
+			}
+			order = order[:len(order)-1]
+			placed[n] = false
+			if !more {
+				return false
+			}
+		}
+		return true
+	}
+	extend()
+	return nil
+}
+
+func lessById(a, b graph.Node) bool {
+	return a.ID() < b.ID()
+}
+
+// nodeQueue is a min-heap of nodes ordered by less, for use with container/heap.
+type nodeQueue struct {
+	nodes []graph.Node
+	less  func(a, b graph.Node) bool
+}
+
+func (q nodeQueue) Len() int {
+	return len(q.nodes)
+}
+
+func (q nodeQueue) Less(i, j int) bool {
+	return q.less(q.nodes[i], q.nodes[j])
+}
+
+func (q nodeQueue) Swap(i, j int) {
+	q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i]
+}
+
+func (q *nodeQueue) Push(x interface{}) {
+	q.nodes = append(q.nodes, x.(graph.Node))
+}
+
+func (q *nodeQueue) Pop() interface{} {
+	n := q.nodes[len(q.nodes)-1]
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return n
+}
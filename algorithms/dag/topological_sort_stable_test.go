@@ -0,0 +1,177 @@
+package dag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestTopologicalSortStable(t *testing.T) {
+	suite.Run(t, new(TopologicalSortStableTestSuite))
+}
+
+type TopologicalSortStableTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TopologicalSortStableTestSuite) generateGraph(nodes []nodePrototype) graph.Graph {
+	g := graph.NewGraph()
+
+	for _, n := range nodes {
+		g.AddDirectedEdge(&graph.Edge{
+			H: graph.Node{Id: n.srcId},
+			T: graph.Node{Id: n.targetId},
+		})
+	}
+
+	return g
+}
+
+func (suite *TopologicalSortStableTestSuite) ids(nodes []graph.Node) []int {
+	result := make([]int, len(nodes))
+	for i, n := range nodes {
+		result[i] = n.ID()
+	}
+	return result
+}
+
+func (suite *TopologicalSortStableTestSuite) TestSort() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{5, 2},
+		{5, 1},
+		{4, 1},
+		{4, 3},
+		{2, 3},
+		{3, 6},
+	})
+
+	for i := 0; i < 10; i++ { // The same order every time
+		nodes, err := TopologicalSortStable(g, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{4, 5, 1, 2, 3, 6}, suite.ids(nodes))
+	}
+}
+
+func (suite *TopologicalSortStableTestSuite) TestSortLess() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{5, 2},
+		{5, 1},
+		{4, 1},
+		{4, 3},
+		{2, 3},
+		{3, 6},
+	})
+
+	nodes, err := TopologicalSortStable(g, func(a, b graph.Node) bool {
+		return a.ID() > b.ID()
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5, 4, 2, 3, 6, 1}, suite.ids(nodes))
+}
+
+func (suite *TopologicalSortStableTestSuite) TestSortCycles() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+		{3, 4},
+		{4, 2},
+		{4, 5},
+	})
+
+	nodes, err := TopologicalSortStable(g, nil)
+	assert.Nil(t, nodes)
+	assert.True(t, errors.Is(err, ErrCycle))
+	var cycleErr *CycleError
+	if assert.True(t, errors.As(err, &cycleErr)) {
+		assert.Len(t, cycleErr.Cycle, 3)
+		for i, n := range cycleErr.Cycle {
+			assert.NotNil(t, g.EdgeTo(n, cycleErr.Cycle[(i+1)%len(cycleErr.Cycle)]))
+		}
+	}
+}
+
+func (suite *TopologicalSortStableTestSuite) TestSortContextCancelled() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	nodes, err := TopologicalSortStableContext(ctx, g, nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, nodes)
+}
+
+func (suite *TopologicalSortStableTestSuite) TestAllTopologicalSorts() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 3},
+		{2, 3},
+		{3, 4},
+		{3, 5},
+	})
+
+	orders := [][]int{}
+	err := AllTopologicalSorts(g, func(order []graph.Node) bool {
+		orders = append(orders, suite.ids(order))
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{
+		{1, 2, 3, 4, 5},
+		{1, 2, 3, 5, 4},
+		{2, 1, 3, 4, 5},
+		{2, 1, 3, 5, 4},
+	}, orders)
+}
+
+func (suite *TopologicalSortStableTestSuite) TestAllTopologicalSortsStop() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{3, 4},
+	})
+
+	// Two independent chains of two nodes interleave in C(4, 2) = 6 ways
+	count := 0
+	err := AllTopologicalSorts(g, func(order []graph.Node) bool {
+		count++
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, count)
+
+	count = 0
+	err = AllTopologicalSorts(g, func(order []graph.Node) bool {
+		count++
+		return count < 2
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func (suite *TopologicalSortStableTestSuite) TestAllTopologicalSortsCycles() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 1},
+	})
+
+	called := false
+	err := AllTopologicalSorts(g, func(order []graph.Node) bool {
+		called = true
+		return true
+	})
+	assert.True(t, errors.Is(err, ErrCycle))
+	assert.False(t, called)
+}
@@ -1,6 +1,7 @@
 package dag
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -84,3 +85,17 @@ func (suite *TopologicalSortTestSuite) TestSortCycles() {
 	assert.Error(t, err)
 	assert.Nil(t, nodes)
 }
+
+func (suite *TopologicalSortTestSuite) TestSortContextCancelled() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	nodes, err := TopologicalSortContext(ctx, g)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, nodes)
+}
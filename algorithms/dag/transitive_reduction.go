@@ -0,0 +1,37 @@
+package dag
+
+import (
+	"github.com/obeattie/vrp/graph"
+)
+
+// TransitiveReduction returns a new graph with the same nodes and reachability as g, but with as few edges as
+// possible: an edge from u to w is dropped if w is also reachable from u by a longer path. The edges which remain are
+// copies of those in g, so keep their costs and capacities.
+//
+// The transitive reduction of a DAG is unique. If the given Graph contains cycles, a *CycleError is returned.
+func TransitiveReduction(g graph.Graph) (graph.Graph, error) {
+	r, err := NewReachability(g)
+	if err != nil {
+		return nil, err
+	}
+
+	result := graph.NewGraph()
+	for _, n := range g.NodeList() {
+		result.AddNode(n)
+	}
+	for _, n := range g.NodeList() {
+		successors := g.Successors(n)
+		// The nodes reachable from n other than directly
+		indirect := newBitset(len(r.descendants))
+		for _, v := range successors {
+			indirect.union(r.descendants[r.index[v]])
+		}
+		for _, w := range successors {
+			if !indirect.has(r.index[w]) {
+				edge := *g.EdgeTo(n, w)
+				result.AddDirectedEdge(&edge)
+			}
+		}
+	}
+	return result, nil
+}
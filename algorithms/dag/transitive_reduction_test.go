@@ -0,0 +1,109 @@
+package dag
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestTransitiveReduction(t *testing.T) {
+	suite.Run(t, new(TransitiveReductionTestSuite))
+}
+
+type TransitiveReductionTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TransitiveReductionTestSuite) edgeCount(g graph.Graph) int {
+	count := 0
+	for _, n := range g.NodeList() {
+		count += len(g.Successors(n))
+	}
+	return count
+}
+
+func (suite *TransitiveReductionTestSuite) TestTransitiveReduction() {
+	t := suite.T()
+	g := generateCostedGraph([]costedEdgePrototype{
+		{1, 2, 1},
+		{2, 3, 2},
+		{1, 3, 5}, // Implied by 1 -> 2 -> 3
+		{3, 4, 3},
+		{1, 4, 7}, // Implied by 1 -> 2 -> 3 -> 4
+		{5, 4, 4},
+	})
+
+	reduced, err := TransitiveReduction(g)
+	assert.NoError(t, err)
+	assert.Len(t, reduced.NodeList(), 5)
+	assert.Equal(t, 4, suite.edgeCount(reduced))
+	assert.Nil(t, reduced.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 3}))
+	assert.Nil(t, reduced.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 4}))
+	if edge := reduced.EdgeTo(graph.Node{Id: 2}, graph.Node{Id: 3}); assert.NotNil(t, edge) {
+		assert.Equal(t, 2.0, edge.Cost)
+	}
+	assert.NotNil(t, reduced.EdgeTo(graph.Node{Id: 5}, graph.Node{Id: 4}))
+	// The original graph is untouched
+	assert.Equal(t, 6, suite.edgeCount(g))
+}
+
+func (suite *TransitiveReductionTestSuite) TestTransitiveReductionIsolatedNodes() {
+	t := suite.T()
+	g := graph.NewGraph()
+	g.AddNode(graph.Node{Id: 1})
+	g.AddNode(graph.Node{Id: 2})
+
+	reduced, err := TransitiveReduction(g)
+	assert.NoError(t, err)
+	assert.Len(t, reduced.NodeList(), 2)
+	assert.Equal(t, 0, suite.edgeCount(reduced))
+}
+
+func (suite *TransitiveReductionTestSuite) TestTransitiveReductionRandom() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(24))
+	for round := 0; round < 10; round++ {
+		g := randomDAG(rng, 30, 0.2)
+
+		reduced, err := TransitiveReduction(g)
+		assert.NoError(t, err)
+		original, err := NewReachability(g)
+		assert.NoError(t, err)
+		after, err := NewReachability(reduced)
+		assert.NoError(t, err)
+		for _, a := range g.NodeList() {
+			for _, b := range g.NodeList() {
+				assert.Equal(t, original.Reaches(a, b), after.Reaches(a, b))
+			}
+		}
+
+		// Every remaining edge is necessary: without it, its tail is no longer reachable from its head
+		for _, n := range reduced.NodeList() {
+			for _, w := range reduced.Successors(n) {
+				without := reduced.Copy()
+				without.RemoveDirectedEdge(reduced.EdgeTo(n, w))
+				r, err := NewReachability(without)
+				assert.NoError(t, err)
+				assert.False(t, r.Reaches(n, w))
+			}
+		}
+	}
+}
+
+func (suite *TransitiveReductionTestSuite) TestTransitiveReductionCycles() {
+	t := suite.T()
+	g := generateCostedGraph([]costedEdgePrototype{
+		{1, 2, 1},
+		{2, 3, 1},
+		{3, 1, 1},
+	})
+
+	reduced, err := TransitiveReduction(g)
+	assert.Nil(t, reduced)
+	assert.True(t, errors.Is(err, ErrCycle))
+}
@@ -0,0 +1,13 @@
+package flow
+
+import (
+	"errors"
+)
+
+var (
+	ErrUnbounded            = errors.New("Flow is unbounded: a path of infinite capacity joins the source and sink")
+	ErrInsufficientCapacity = errors.New("Insufficient capacity for the required flow")
+)
+
+// The relative tolerance for rounding error in sums of costs
+const epsilon = 1e-9
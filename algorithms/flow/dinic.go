@@ -0,0 +1,116 @@
+package flow
+
+import (
+	"math"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// An arc in a residual network
+type arc struct {
+	to       int
+	capacity float64 // Residual capacity
+	reverse  int     // Index of the opposing arc within the arcs of to
+	edge     bool    // Whether the arc is an edge of the graph (rather than the reverse of one)
+}
+
+// MaxFlow returns a maximum flow from source to sink, along edges with positive capacity. Edge costs are ignored in
+// finding the flow (though the cost of the flow is reported).
+//
+// This algorithm is Dinic's [1], which runs in O(V²E) time. If the flow is unbounded (because a path of edges with
+// infinite capacity joins the source and sink), ErrUnbounded is returned.
+//
+// [1] Dinic, E. A. Algorithm for Solution of a Problem of Maximum Flow in a Network with Power Estimation (1970).
+func MaxFlow(g graph.Graph, source, sink graph.Node) (*Flow, error) {
+	nodes := g.NodeList()
+	index := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		index[n.ID()] = i
+	}
+	s, sOk := index[source.ID()]
+	t, tOk := index[sink.ID()]
+	if !sOk || !tOk || s == t {
+		return newFlow(g, source, nil), nil
+	}
+
+	arcs := make([][]arc, len(nodes))
+	for u, n := range nodes {
+		for _, successor := range g.Successors(n) {
+			v := index[successor.ID()]
+			capacity := g.EdgeTo(n, successor).Capacity
+			if u == v || capacity <= 0 {
+				continue
+			}
+			arcs[u] = append(arcs[u], arc{to: v, capacity: capacity, reverse: len(arcs[v]), edge: true})
+			arcs[v] = append(arcs[v], arc{to: u, reverse: len(arcs[u]) - 1})
+		}
+	}
+
+	levels, next := make([]int, len(nodes)), make([]int, len(nodes))
+	// bfs assigns each node its distance (in arcs) from s through the residual network, returning whether t is reached
+	bfs := func() bool {
+		for i := range levels {
+			levels[i] = -1
+		}
+		levels[s] = 0
+		queue := []int{s}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, a := range arcs[u] {
+				if a.capacity > 0 && levels[a.to] < 0 {
+					levels[a.to] = levels[u] + 1
+					queue = append(queue, a.to)
+				}
+			}
+		}
+		return levels[t] >= 0
+	}
+	// dfs pushes up to limit units of flow from u to t along arcs which lead strictly deeper, returning the amount
+	var dfs func(u int, limit float64) float64
+	dfs = func(u int, limit float64) float64 {
+		if u == t {
+			return limit
+		}
+		for ; next[u] < len(arcs[u]); next[u]++ {
+			a := &arcs[u][next[u]]
+			if a.capacity <= 0 || levels[a.to] != levels[u]+1 {
+				continue
+			}
+			if pushed := dfs(a.to, math.Min(limit, a.capacity)); pushed > 0 {
+				a.capacity -= pushed
+				arcs[a.to][a.reverse].capacity += pushed
+				return pushed
+			}
+		}
+		return 0
+	}
+
+	// Each phase saturates every shortest augmenting path (a blocking flow)
+	for bfs() {
+		for i := range next {
+			next[i] = 0
+		}
+		for {
+			pushed := dfs(s, math.Inf(0))
+			if math.IsInf(pushed, 1) {
+				return nil, ErrUnbounded
+			} else if pushed == 0 {
+				break
+			}
+		}
+	}
+
+	// The flow along each edge is the capacity it has used
+	flows := map[[2]int]float64{}
+	for u, uArcs := range arcs {
+		for _, a := range uArcs {
+			if a.edge {
+				if used := arcs[a.to][a.reverse].capacity; used > 0 {
+					flows[[2]int{nodes[u].ID(), nodes[a.to].ID()}] = used
+				}
+			}
+		}
+	}
+	return newFlow(g, source, flows), nil
+}
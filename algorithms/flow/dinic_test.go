@@ -0,0 +1,188 @@
+package flow
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+type edgePrototype struct {
+	srcId, targetId int
+	capacity, cost  float64
+}
+
+func generateGraph(edges []edgePrototype) graph.Graph {
+	g := graph.NewGraph()
+	for _, e := range edges {
+		g.AddDirectedEdge(&graph.Edge{
+			H:        graph.Node{Id: e.srcId},
+			T:        graph.Node{Id: e.targetId},
+			Cost:     e.cost,
+			Capacity: e.capacity,
+		})
+	}
+	return g
+}
+
+// randomGraph generates a graph of n nodes and m edges, with random integral capacities and costs
+func randomGraph(rng *rand.Rand, n, m int) graph.Graph {
+	g := graph.NewGraph()
+	for i := 1; i <= n; i++ {
+		g.AddNode(graph.Node{Id: i})
+	}
+	for i := 0; i < m; i++ {
+		g.AddDirectedEdge(&graph.Edge{
+			H:        graph.Node{Id: 1 + rng.Intn(n)},
+			T:        graph.Node{Id: 1 + rng.Intn(n)},
+			Cost:     float64(rng.Intn(20)),
+			Capacity: float64(rng.Intn(10)),
+		})
+	}
+	return g
+}
+
+// assertFeasible checks that a flow respects capacities and is conserved at every node but the source and sink
+func assertFeasible(t *testing.T, g graph.Graph, f *Flow, source, sink graph.Node) {
+	net := map[int]float64{}
+	for _, ef := range f.Edges {
+		assert.True(t, ef.Flow > 0)
+		assert.True(t, ef.Flow <= ef.Edge.Capacity+1e-9, "Edge %d -> %d over capacity", ef.Edge.H.ID(), ef.Edge.T.ID())
+		assert.Equal(t, ef.Flow, f.On(ef.Edge.H, ef.Edge.T))
+		net[ef.Edge.H.ID()] -= ef.Flow
+		net[ef.Edge.T.ID()] += ef.Flow
+	}
+	for id, n := range net {
+		switch id {
+		case source.ID():
+			assert.InDelta(t, -f.Value, n, 1e-9)
+		case sink.ID():
+			assert.InDelta(t, f.Value, n, 1e-9)
+		default:
+			assert.InDelta(t, 0, n, 1e-9, "Flow not conserved at %d", id)
+		}
+	}
+}
+
+func TestMaxFlow(t *testing.T) {
+	suite.Run(t, new(MaxFlowTestSuite))
+}
+
+type MaxFlowTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MaxFlowTestSuite) TestSimple() {
+	t := suite.T()
+	// From Cormen et al., Introduction to Algorithms
+	g := generateGraph([]edgePrototype{
+		{1, 2, 16, 1},
+		{1, 3, 13, 1},
+		{2, 4, 12, 1},
+		{3, 2, 4, 1},
+		{3, 5, 14, 1},
+		{4, 3, 9, 1},
+		{4, 6, 20, 1},
+		{5, 4, 7, 1},
+		{5, 6, 4, 1},
+		{6, 1, 0, 1}, // No capacity
+	})
+
+	f, err := MaxFlow(g, graph.Node{Id: 1}, graph.Node{Id: 6})
+	assert.NoError(t, err)
+	assert.Equal(t, 23.0, f.Value)
+	// The minimum cut is saturated
+	assert.Equal(t, 12.0, f.On(graph.Node{Id: 2}, graph.Node{Id: 4}))
+	assert.Equal(t, 7.0, f.On(graph.Node{Id: 5}, graph.Node{Id: 4}))
+	assert.Equal(t, 4.0, f.On(graph.Node{Id: 5}, graph.Node{Id: 6}))
+	assert.Equal(t, 0.0, f.On(graph.Node{Id: 6}, graph.Node{Id: 1}))
+	assertFeasible(t, g, f, graph.Node{Id: 1}, graph.Node{Id: 6})
+	for i := 1; i < len(f.Edges); i++ {
+		assert.True(t, f.Edges[i-1].Edge.H.ID() <= f.Edges[i].Edge.H.ID())
+	}
+
+	// Nothing flows backwards
+	f, err = MaxFlow(g, graph.Node{Id: 6}, graph.Node{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, f.Value)
+	assert.Len(t, f.Edges, 0)
+}
+
+func (suite *MaxFlowTestSuite) TestAntiparallelEdges() {
+	t := suite.T()
+	g := generateGraph([]edgePrototype{
+		{1, 2, 3, 0},
+		{2, 1, 5, 0},
+		{2, 3, 2, 0},
+		{1, 3, 1, 0},
+	})
+
+	f, err := MaxFlow(g, graph.Node{Id: 1}, graph.Node{Id: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 3.0, f.Value)
+	assertFeasible(t, g, f, graph.Node{Id: 1}, graph.Node{Id: 3})
+}
+
+func (suite *MaxFlowTestSuite) TestDegenerate() {
+	t := suite.T()
+	g := generateGraph([]edgePrototype{
+		{1, 2, math.Inf(0), 0},
+		{2, 3, math.Inf(0), 0},
+	})
+
+	_, err := MaxFlow(g, graph.Node{Id: 1}, graph.Node{Id: 3})
+	assert.Equal(t, ErrUnbounded, err)
+
+	f, err := MaxFlow(g, graph.Node{Id: 1}, graph.Node{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, f.Value)
+	f, err = MaxFlow(g, graph.Node{Id: 1}, graph.Node{Id: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, f.Value)
+}
+
+func (suite *MaxFlowTestSuite) TestMinCut() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(20))
+
+	for i := 0; i < 20; i++ {
+		g := randomGraph(rng, 30, 120)
+		source, sink := graph.Node{Id: 1}, graph.Node{Id: 30}
+		f, err := MaxFlow(g, source, sink)
+		assert.NoError(t, err)
+		assertFeasible(t, g, f, source, sink)
+
+		// The flow is maximal if and only if it saturates some cut: the edges leaving the nodes the source can still
+		// reach through the residual network
+		residual := newResidualGraph(g)
+		for _, ef := range f.Edges {
+			residual.flows[[2]int{ef.Edge.H.ID(), ef.Edge.T.ID()}] = ef.Flow
+		}
+		reached := map[int]bool{source.ID(): true}
+		queue := []graph.Node{source}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			for _, successor := range residual.Successors(n) {
+				if !reached[successor.ID()] {
+					reached[successor.ID()] = true
+					queue = append(queue, successor)
+				}
+			}
+		}
+		assert.False(t, reached[sink.ID()])
+		cut := 0.0
+		for _, n := range g.NodeList() {
+			for _, successor := range g.Successors(n) {
+				if reached[n.ID()] && !reached[successor.ID()] {
+					cut += g.EdgeTo(n, successor).Capacity
+				}
+			}
+		}
+		assert.Equal(t, cut, f.Value)
+	}
+}
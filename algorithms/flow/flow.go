@@ -0,0 +1,75 @@
+// Package flow solves network flow problems over graphs, reading the capacity of each edge from its Capacity.
+package flow
+
+import (
+	"sort"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// An EdgeFlow is the flow along a single edge.
+type EdgeFlow struct {
+	Edge *graph.Edge
+	Flow float64
+}
+
+// A Flow is a flow from a source to a sink.
+type Flow struct {
+	// Value is the net flow out of the source (and into the sink).
+	Value float64
+	// Cost is the total cost of the flow: the sum over each edge of its flow and cost.
+	Cost float64
+	// Edges are the edges carrying flow, in order of their heads' and then their tails' IDs.
+	Edges []EdgeFlow
+	flows map[[2]int]float64 // By (head, tail) node IDs
+}
+
+// newFlow returns the Flow made up of the given flows along the edges of g.
+func newFlow(g graph.Graph, source graph.Node, flows map[[2]int]float64) *Flow {
+	f := &Flow{
+		Edges: []EdgeFlow{},
+		flows: map[[2]int]float64{},
+	}
+	for _, n := range g.NodeList() {
+		for _, successor := range g.Successors(n) {
+			amount := flows[[2]int{n.ID(), successor.ID()}]
+			if amount <= 0 {
+				continue
+			}
+			edge := g.EdgeTo(n, successor)
+			f.Edges = append(f.Edges, EdgeFlow{Edge: edge, Flow: amount})
+			f.flows[[2]int{n.ID(), successor.ID()}] = amount
+			f.Cost += amount * edge.Cost
+			if n.ID() == source.ID() {
+				f.Value += amount
+			} else if successor.ID() == source.ID() {
+				f.Value -= amount
+			}
+		}
+	}
+	sort.Sort(edgeFlowsByNodes(f.Edges))
+	return f
+}
+
+// On returns the flow along the edge from head to tail.
+func (f *Flow) On(head, tail graph.Node) float64 {
+	return f.flows[[2]int{head.ID(), tail.ID()}]
+}
+
+// edgeFlowsByNodes sorts edge flows by the IDs of their heads, then tails
+type edgeFlowsByNodes []EdgeFlow
+
+func (e edgeFlowsByNodes) Len() int {
+	return len(e)
+}
+
+func (e edgeFlowsByNodes) Less(i, j int) bool {
+	if e[i].Edge.H.ID() != e[j].Edge.H.ID() {
+		return e[i].Edge.H.ID() < e[j].Edge.H.ID()
+	}
+	return e[i].Edge.T.ID() < e[j].Edge.T.ID()
+}
+
+func (e edgeFlowsByNodes) Swap(i, j int) {
+	e[i], e[j] = e[j], e[i]
+}
@@ -0,0 +1,96 @@
+package flow
+
+import (
+	"math"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/graph"
+)
+
+// MinCostFlow returns the cheapest flow of the given amount from source to sink, along edges with positive capacity.
+// If the edges don't have the capacity for the full amount, the cheapest maximum flow is returned along with
+// ErrInsufficientCapacity.
+//
+// This is the successive shortest paths algorithm: flow is repeatedly sent along the cheapest path through the
+// residual network, found by Dijkstra's algorithm over costs reduced by node potentials (which keep them
+// non-negative). Edge costs may be negative, in which case the initial potentials are found by Bellman-Ford; if there
+// is a negative-cost cycle, a *shortestpaths.NegativeCycleError is returned. Should a reduced cost ever be negative
+// by more than rounding error, shortestpaths.ErrContradiction is returned rather than a flow which may not be optimal.
+func MinCostFlow(g graph.Graph, source, sink graph.Node, amount float64) (*Flow, error) {
+	residual := newResidualGraph(g)
+	if source.ID() == sink.ID() || amount <= 0 {
+		return newFlow(g, source, residual.flows), nil
+	}
+
+	// Initial potentials are zero, unless negative costs make shortest path costs necessary
+	if hasNegativeCost(g) {
+		tree, err := shortestpaths.BellmanFord(residual, source)
+		if err != nil {
+			return nil, err
+		}
+		residual.potentials = make(map[int]float64, len(tree.Costs))
+		for n, cost := range tree.Costs {
+			residual.potentials[n.ID()] = cost
+		}
+	} else {
+		residual.potentials = map[int]float64{}
+	}
+
+	sent := 0.0
+	for sent < amount {
+		tree, err := shortestpaths.SingleSourceDijkstra(residual, source, math.Inf(0))
+		if err == nil {
+			err = residual.err
+		}
+		if err != nil {
+			return nil, err
+		}
+		path, err := tree.PathTo(sink)
+		if err == shortestpaths.ErrUnreachable {
+			return newFlow(g, source, residual.flows), ErrInsufficientCapacity
+		} else if err != nil {
+			return nil, err
+		}
+
+		// Send as much as the path can take
+		bottleneck := amount - sent
+		for _, e := range path.Edges {
+			bottleneck = math.Min(bottleneck, e.Capacity)
+		}
+		if math.IsInf(bottleneck, 1) {
+			return nil, ErrUnbounded
+		}
+		for _, e := range path.Edges {
+			residual.augment(e.H, e.T, bottleneck)
+		}
+		sent += bottleneck
+
+		// Shortest path costs become the new potentials, keeping reduced costs non-negative
+		for n, cost := range tree.Costs {
+			residual.potentials[n.ID()] += cost
+		}
+	}
+
+	return newFlow(g, source, residual.flows), nil
+}
+
+// MinCostMaxFlow returns the cheapest of the maximum flows from source to sink. See MinCostFlow.
+func MinCostMaxFlow(g graph.Graph, source, sink graph.Node) (*Flow, error) {
+	f, err := MinCostFlow(g, source, sink, math.Inf(0))
+	if err == ErrInsufficientCapacity { // Expected, since no amount is enough
+		err = nil
+	}
+	return f, err
+}
+
+// hasNegativeCost returns whether any edge with positive capacity has a negative cost.
+func hasNegativeCost(g graph.Graph) bool {
+	for _, n := range g.NodeList() {
+		for _, successor := range g.Successors(n) {
+			if e := g.EdgeTo(n, successor); e.Capacity > 0 && e.Cost < 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package flow
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestMinCostFlow(t *testing.T) {
+	suite.Run(t, new(MinCostFlowTestSuite))
+}
+
+type MinCostFlowTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MinCostFlowTestSuite) TestSimple() {
+	t := suite.T()
+	// Two routes from 1 to 4: a cheap one of capacity 2, and a dearer one of capacity 3
+	g := generateGraph([]edgePrototype{
+		{1, 2, 2, 1},
+		{2, 4, 2, 1},
+		{1, 3, 3, 2},
+		{3, 4, 3, 2},
+		{2, 3, 1, 0},
+	})
+
+	f, err := MinCostFlow(g, graph.Node{Id: 1}, graph.Node{Id: 4}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, f.Value)
+	assert.Equal(t, 4.0, f.Cost)
+	assert.Equal(t, 2.0, f.On(graph.Node{Id: 2}, graph.Node{Id: 4}))
+	assertFeasible(t, g, f, graph.Node{Id: 1}, graph.Node{Id: 4})
+
+	f, err = MinCostFlow(g, graph.Node{Id: 1}, graph.Node{Id: 4}, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, f.Value)
+	assert.Equal(t, 4.0+8.0, f.Cost)
+	assertFeasible(t, g, f, graph.Node{Id: 1}, graph.Node{Id: 4})
+
+	f, err = MinCostFlow(g, graph.Node{Id: 1}, graph.Node{Id: 4}, 10)
+	assert.Equal(t, ErrInsufficientCapacity, err)
+	assert.Equal(t, 5.0, f.Value)
+	assert.Equal(t, 4.0+12.0, f.Cost)
+
+	f, err = MinCostMaxFlow(g, graph.Node{Id: 1}, graph.Node{Id: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, f.Value)
+	assert.Equal(t, 16.0, f.Cost)
+}
+
+func (suite *MinCostFlowTestSuite) TestCancellation() {
+	t := suite.T()
+	// The cheapest single path (1, 2, 3, 4) must be partly undone to send two units
+	g := generateGraph([]edgePrototype{
+		{1, 2, 1, 1},
+		{2, 3, 1, 1},
+		{3, 4, 1, 1},
+		{1, 3, 1, 3},
+		{2, 4, 1, 3},
+	})
+
+	f, err := MinCostMaxFlow(g, graph.Node{Id: 1}, graph.Node{Id: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, f.Value)
+	assert.Equal(t, 8.0, f.Cost)
+	assert.Equal(t, 0.0, f.On(graph.Node{Id: 2}, graph.Node{Id: 3}))
+}
+
+func (suite *MinCostFlowTestSuite) TestAssignment() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(20))
+
+	// Assigning 4 vehicles (2-5) to 4 depots (6-9), each with a single space
+	for i := 0; i < 10; i++ {
+		costs := [4][4]float64{}
+		edges := []edgePrototype{}
+		for v := 0; v < 4; v++ {
+			edges = append(edges, edgePrototype{1, 2 + v, 1, 0}, edgePrototype{6 + v, 10, 1, 0})
+			for d := 0; d < 4; d++ {
+				costs[v][d] = float64(rng.Intn(50) - 10) // Some negative
+				edges = append(edges, edgePrototype{2 + v, 6 + d, 1, costs[v][d]})
+			}
+		}
+		g := generateGraph(edges)
+
+		// Brute force over every permutation
+		best := 1e9
+		var permute func(v int, used [4]bool, cost float64)
+		permute = func(v int, used [4]bool, cost float64) {
+			if v == 4 {
+				if cost < best {
+					best = cost
+				}
+				return
+			}
+			for d := 0; d < 4; d++ {
+				if !used[d] {
+					used[d] = true
+					permute(v+1, used, cost+costs[v][d])
+					used[d] = false
+				}
+			}
+		}
+		permute(0, [4]bool{}, 0)
+
+		f, err := MinCostFlow(g, graph.Node{Id: 1}, graph.Node{Id: 10}, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, 4.0, f.Value)
+		assert.Equal(t, best, f.Cost)
+		assertFeasible(t, g, f, graph.Node{Id: 1}, graph.Node{Id: 10})
+	}
+}
+
+func (suite *MinCostFlowTestSuite) TestMatchesMaxFlow() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(20))
+
+	for i := 0; i < 20; i++ {
+		g := randomGraph(rng, 30, 120)
+		source, sink := graph.Node{Id: 1}, graph.Node{Id: 30}
+		max, err := MaxFlow(g, source, sink)
+		assert.NoError(t, err)
+		f, err := MinCostMaxFlow(g, source, sink)
+		assert.NoError(t, err)
+		assert.Equal(t, max.Value, f.Value)
+		assert.True(t, f.Cost <= max.Cost)
+		assertFeasible(t, g, f, source, sink)
+	}
+}
+
+func (suite *MinCostFlowTestSuite) TestNegativeCycle() {
+	t := suite.T()
+	g := generateGraph([]edgePrototype{
+		{1, 2, 1, 1},
+		{2, 3, 1, -2},
+		{3, 2, 1, 1},
+		{3, 4, 1, 1},
+	})
+
+	_, err := MinCostFlow(g, graph.Node{Id: 1}, graph.Node{Id: 4}, 1)
+	assert.True(t, errors.Is(err, shortestpaths.ErrNegativeCycle))
+}
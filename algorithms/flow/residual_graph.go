@@ -0,0 +1,127 @@
+package flow
+
+import (
+	"math"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/graph"
+)
+
+// residualGraph is a read-only view of the residual network of a flow through a graph, in which shortest path
+// algorithms can search for augmenting paths. Each edge with spare capacity appears as itself, and each edge carrying
+// flow also appears reversed (with negated cost), as sending flow back along it cancels some of its flow.
+//
+// Where both are available between a pair of nodes, the cheaper arc is used. If potentials are set, costs are reduced
+// by them, so that they are non-negative once the potentials are the costs of shortest paths. If any is found to be
+// negative by more than rounding error, err is set to shortestpaths.ErrContradiction.
+type residualGraph struct {
+	graph.ReadOnly
+	flows      map[[2]int]float64 // By (head, tail) node IDs
+	potentials map[int]float64    // By node ID
+	err        error              // Set once an arc is found with a negative reduced cost
+}
+
+func newResidualGraph(g graph.Graph) *residualGraph {
+	return &residualGraph{
+		ReadOnly: graph.ReadOnly{Graph: g},
+		flows:    map[[2]int]float64{},
+	}
+}
+
+// arc returns the cost and residual capacity of the cheapest residual arc from u to v, and whether it cancels flow along
+// the edge from v to u. If there is no such arc, its capacity is zero.
+func (g *residualGraph) arc(u, v graph.Node) (cost, capacity float64, backward bool) {
+	if u.ID() == v.ID() { // Loops can never be part of a shortest path
+		return 0, 0, false
+	}
+	if e := g.Graph.EdgeTo(u, v); e != nil {
+		if spare := e.Capacity - g.flows[[2]int{u.ID(), v.ID()}]; spare > 0 {
+			cost, capacity = e.Cost, spare
+		}
+	}
+	if flow := g.flows[[2]int{v.ID(), u.ID()}]; flow > 0 {
+		if e := g.Graph.EdgeTo(v, u); capacity == 0 || -e.Cost < cost {
+			cost, capacity, backward = -e.Cost, flow, true
+		}
+	}
+	if g.potentials != nil && capacity > 0 {
+		pu, pv := g.potentials[u.ID()], g.potentials[v.ID()]
+		reduced := cost + pu - pv
+		// Reduced costs are never negative, except by rounding error. Anything more means the potentials are wrong
+		if reduced < 0 {
+			if -reduced > epsilon*(1+math.Abs(cost)+math.Abs(pu)+math.Abs(pv)) {
+				g.err = shortestpaths.ErrContradiction
+			} else {
+				reduced = 0
+			}
+		}
+		cost = reduced
+	}
+	return cost, capacity, backward
+}
+
+// augment sends amount of flow along the residual arc from u to v.
+func (g *residualGraph) augment(u, v graph.Node, amount float64) {
+	if _, _, backward := g.arc(u, v); backward {
+		g.flows[[2]int{v.ID(), u.ID()}] -= amount
+	} else {
+		g.flows[[2]int{u.ID(), v.ID()}] += amount
+	}
+}
+
+func (g *residualGraph) adjacent(n graph.Node, candidates []graph.Node, outgoing bool) []graph.Node {
+	result := make([]graph.Node, 0, len(candidates))
+	seen := make(map[int]bool, len(candidates))
+	for _, c := range candidates {
+		u, v := n, c
+		if !outgoing {
+			u, v = c, n
+		}
+		if _, capacity, _ := g.arc(u, v); capacity > 0 && !seen[c.ID()] {
+			seen[c.ID()] = true
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func (g *residualGraph) Successors(n graph.Node) []graph.Node {
+	return g.adjacent(n, append(g.Graph.Successors(n), g.Graph.Predecessors(n)...), true)
+}
+
+func (g *residualGraph) Predecessors(n graph.Node) []graph.Node {
+	return g.adjacent(n, append(g.Graph.Predecessors(n), g.Graph.Successors(n)...), false)
+}
+
+func (g *residualGraph) Neighbors(n graph.Node) []graph.Node {
+	return g.adjacent(n, g.Graph.Neighbors(n), true)
+}
+
+func (g *residualGraph) EdgeTo(n, successor graph.Node) *graph.Edge {
+	cost, capacity, _ := g.arc(n, successor)
+	if capacity <= 0 {
+		return nil
+	}
+	return &graph.Edge{
+		H:        n,
+		T:        successor,
+		Cost:     cost,
+		Capacity: capacity,
+	}
+}
+
+func (g *residualGraph) EdgeBetween(n, neighbour graph.Node) *graph.Edge {
+	if e := g.EdgeTo(n, neighbour); e != nil {
+		return e
+	}
+	return g.EdgeTo(neighbour, n)
+}
+
+func (g *residualGraph) Cost(e *graph.Edge) float64 {
+	return e.Cost
+}
+
+// Copy returns a mutable copy of the residual network.
+func (g *residualGraph) Copy() graph.Graph {
+	return graph.CopyOf(g)
+}
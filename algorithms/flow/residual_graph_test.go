@@ -0,0 +1,58 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestResidualGraph(t *testing.T) {
+	g := generateGraph([]edgePrototype{
+		{1, 2, 3, 4},
+		{2, 1, 1, 1},
+		{2, 3, 2, 1},
+	})
+	r := newResidualGraph(g)
+	one, two, three := graph.Node{Id: 1}, graph.Node{Id: 2}, graph.Node{Id: 3}
+
+	assert.Len(t, r.Successors(two), 2)
+	assert.Nil(t, r.EdgeTo(three, two))
+	e := r.EdgeTo(one, two)
+	assert.Equal(t, 4.0, e.Cost)
+	assert.Equal(t, 3.0, e.Capacity)
+
+	// With flow along 1 -> 2, cancelling it (cost -4) is cheaper than using the edge 2 -> 1 (cost 1)
+	r.augment(one, two, 2)
+	r.augment(two, three, 2)
+	e = r.EdgeTo(two, one)
+	assert.Equal(t, -4.0, e.Cost)
+	assert.Equal(t, 2.0, e.Capacity)
+	assert.Equal(t, 1.0, r.EdgeTo(one, two).Capacity)
+	assert.Nil(t, r.EdgeTo(two, three)) // Saturated
+	assert.Equal(t, -1.0, r.EdgeTo(three, two).Cost)
+	assert.Len(t, r.Predecessors(two), 2)
+
+	r.augment(two, one, 2)
+	assert.Equal(t, 0.0, r.flows[[2]int{1, 2}])
+	e = r.EdgeTo(two, one)
+	assert.Equal(t, 1.0, e.Cost)
+
+	// Reduced costs
+	r.potentials = map[int]float64{1: 0, 2: 3}
+	assert.Equal(t, 1.0, r.EdgeTo(one, two).Cost)
+	assert.Equal(t, 4.0, r.EdgeTo(two, one).Cost)
+
+	// Rounding error is clamped away, but a genuinely negative reduced cost is a contradiction
+	r.potentials = map[int]float64{1: 0, 2: 4 + 1e-12}
+	assert.Equal(t, 0.0, r.EdgeTo(one, two).Cost)
+	assert.NoError(t, r.err)
+	r.potentials = map[int]float64{1: 0, 2: 4.5}
+	assert.Equal(t, -0.5, r.EdgeTo(one, two).Cost)
+	assert.Equal(t, shortestpaths.ErrContradiction, r.err)
+
+	assert.Panics(t, func() { r.AddNode(graph.Node{Id: 4}) })
+	assert.NotNil(t, r.Copy().EdgeTo(three, two))
+}
@@ -0,0 +1,290 @@
+package shortestpaths
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+var ErrInvalidPenalty = errors.New("Penalty must be positive")
+
+// An AlternativeMethod is a way of generating alternative routes.
+type AlternativeMethod int
+
+const (
+	// PenaltyMethod repeatedly searches for the shortest path, penalising the edges of each path found so that later
+	// searches are steered away from them.
+	PenaltyMethod AlternativeMethod = iota
+	// PlateauMethod joins the shortest path trees from the source and (backwards) from the target. Stretches of road
+	// which lie on both trees ("plateaus") are taken to be the backbones of natural alternatives.
+	PlateauMethod
+)
+
+const (
+	DefaultMaxAlternatives = 3
+	DefaultMaxOverlap      = 0.5
+	DefaultMaxStretch      = 1.5
+	DefaultPenalty         = 0.5
+)
+
+// AlternativeOptions configure the search for alternative routes. Every option is used as given, so zero values are
+// meaningful (a MaxOverlap of zero asks for fully disjoint alternatives); start from DefaultAlternativeOptions to
+// change only some of them.
+type AlternativeOptions struct {
+	Method AlternativeMethod
+	// MaxAlternatives is the number of paths to return at most, including the shortest path.
+	MaxAlternatives int
+	// MaxOverlap is the greatest fraction of an alternative's cost which may be shared with the paths before it.
+	MaxOverlap float64
+	// MaxStretch is the greatest ratio of an alternative's cost to that of the shortest path.
+	MaxStretch float64
+	// Penalty is the fraction by which the cost of an edge is increased each time the penalty method finds a path using
+	// it. It must be positive, or ErrInvalidPenalty is returned. The search stops once it finds a path it has found
+	// before, so larger penalties steer it further from the shortest path.
+	Penalty float64
+}
+
+// DefaultAlternativeOptions returns options using the penalty method, with every other option set to its default.
+func DefaultAlternativeOptions() AlternativeOptions {
+	return AlternativeOptions{
+		Method:          PenaltyMethod,
+		MaxAlternatives: DefaultMaxAlternatives,
+		MaxOverlap:      DefaultMaxOverlap,
+		MaxStretch:      DefaultMaxStretch,
+		Penalty:         DefaultPenalty,
+	}
+}
+
+// An Alternative is a path returned by Alternatives.
+type Alternative struct {
+	Path
+	// Overlap is the fraction of the path's cost which is shared with the paths returned before it.
+	Overlap float64
+}
+
+// Alternatives returns meaningfully different paths from source to target. The first is always the shortest path
+// (as returned by DijkstraPath), with the others following in the order they were found. Unlike those returned by
+// KShortestPaths, each alternative overlaps the paths before it by no more than opts.MaxOverlap, and costs no more
+// than opts.MaxStretch times the shortest path. Fewer than opts.MaxAlternatives paths are returned if no more are
+// found, but the shortest path is always returned.
+func Alternatives(g graph.Graph, source, target graph.Node, opts AlternativeOptions) ([]Alternative, error) {
+	if opts.Method == PenaltyMethod && opts.Penalty <= 0 {
+		return nil, ErrInvalidPenalty
+	}
+	shortest, err := DijkstraPath(g, source, target)
+	if err != nil {
+		return nil, err
+	}
+	s := &alternativeSelection{
+		opts:     opts,
+		maxCost:  shortest.Cost * opts.MaxStretch,
+		selected: []Path{shortest},
+		results:  []Alternative{{Path: shortest}},
+		shared:   map[[2]int]bool{},
+	}
+	s.share(shortest)
+
+	switch opts.Method {
+	case PenaltyMethod:
+		err = s.penalty(g, source, target)
+	case PlateauMethod:
+		err = s.plateaus(g, source, target)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.results, nil
+}
+
+// alternativeSelection accumulates the alternatives accepted so far
+type alternativeSelection struct {
+	opts     AlternativeOptions
+	maxCost  float64
+	selected []Path
+	results  []Alternative
+	shared   map[[2]int]bool // Edges (by head and tail ID) on the paths accepted so far
+}
+
+func (s *alternativeSelection) done() bool {
+	return len(s.results) >= s.opts.MaxAlternatives
+}
+
+func (s *alternativeSelection) share(p Path) {
+	for _, e := range p.Edges {
+		s.shared[[2]int{e.H.ID(), e.T.ID()}] = true
+	}
+}
+
+// overlap returns the fraction of the cost of p which is shared with the paths accepted so far
+func (s *alternativeSelection) overlap(p Path) float64 {
+	if p.Cost == 0 {
+		return 0
+	}
+	shared := 0.0
+	for _, e := range p.Edges {
+		if s.shared[[2]int{e.H.ID(), e.T.ID()}] {
+			shared += e.Cost
+		}
+	}
+	return shared / p.Cost
+}
+
+// consider accepts p as an alternative if it meets the options.
+func (s *alternativeSelection) consider(p Path) {
+	if s.done() || p.Cost > s.maxCost || containsPath(s.selected, p) {
+		return
+	}
+	overlap := s.overlap(p)
+	if overlap > s.opts.MaxOverlap {
+		return
+	}
+	s.selected = append(s.selected, p)
+	s.results = append(s.results, Alternative{Path: p, Overlap: overlap})
+	s.share(p)
+}
+
+func (s *alternativeSelection) penalty(g graph.Graph, source, target graph.Node) error {
+	view := newPenalisedGraph(g)
+	last := s.selected[0]
+	considered := []Path{last}
+	// Each round penalises the path found in the last. Not every path found is accepted, and once a path is found again
+	// the penalties are no longer steering the search anywhere new
+	for !s.done() {
+		for _, e := range last.Edges {
+			view.penalise(e.H, e.T, 1+s.opts.Penalty)
+		}
+
+		found, err := DijkstraPath(view, source, target)
+		if err != nil {
+			return err
+		}
+		// The path's real costs are those of the underlying graph
+		p, err := NewPath(g, found.Nodes)
+		if err != nil {
+			return err
+		}
+		if containsPath(considered, p) {
+			break
+		}
+		considered = append(considered, p)
+		s.consider(p)
+		last = p
+	}
+	return nil
+}
+
+func (s *alternativeSelection) plateaus(g graph.Graph, source, target graph.Node) error {
+	forward, err := SingleSourceDijkstra(g, source, s.maxCost)
+	if err != nil {
+		return err
+	}
+	backward, err := SingleSourceDijkstra(reversedGraph{graph.ReadOnly{Graph: g}}, target, s.maxCost)
+	if err != nil {
+		return err
+	}
+
+	// The next node towards the target on u's backward tree path, if the edge to it also lies on the forward tree
+	plateauNext := func(u graph.Node) (graph.Node, bool) {
+		if u == target {
+			return graph.Node{}, false
+		}
+		v, ok := backward.Predecessors[u]
+		if !ok || !forward.Reachable(v) {
+			return graph.Node{}, false
+		}
+		if p, ok := forward.Predecessors[v]; !ok || p != u {
+			return graph.Node{}, false
+		}
+		return v, true
+	}
+
+	plateaus := []plateau{}
+	for u := range forward.Costs {
+		if !backward.Reachable(u) {
+			continue
+		}
+		// Plateaus are walked from their first node: one which the plateau doesn't enter. A lone node is a plateau of
+		// no length, giving the shortest path via that node.
+		if p, ok := forward.Predecessors[u]; ok {
+			if next, ok := plateauNext(p); ok && next == u {
+				continue
+			}
+		}
+		pl := plateau{nodes: []graph.Node{u}}
+		for v, ok := plateauNext(u); ok; v, ok = plateauNext(v) {
+			pl.nodes = append(pl.nodes, v)
+		}
+		pl.length = forward.Cost(pl.nodes[len(pl.nodes)-1]) - forward.Cost(u)
+		plateaus = append(plateaus, pl)
+	}
+	// Longer plateaus make for more natural alternatives
+	sort.Sort(plateausByLength(plateaus))
+
+	for _, pl := range plateaus {
+		if s.done() {
+			break
+		}
+		start, end := pl.nodes[0], pl.nodes[len(pl.nodes)-1]
+		if forward.Cost(start)+pl.length+backward.Cost(end) > s.maxCost {
+			continue
+		}
+
+		// Source to the start of the plateau, then the plateau, then its end to the target
+		toStart, err := forward.PathTo(start)
+		if err != nil {
+			return err
+		}
+		nodes := append([]graph.Node{}, toStart.Nodes...)
+		nodes = append(nodes, pl.nodes[1:]...)
+		for n := end; n != target; {
+			n = backward.Predecessors[n]
+			nodes = append(nodes, n)
+		}
+		if !simple(nodes) {
+			continue
+		}
+		p, err := NewPath(g, nodes)
+		if err != nil {
+			return err
+		}
+		s.consider(p)
+	}
+	return nil
+}
+
+// simple returns whether no node appears more than once.
+func simple(nodes []graph.Node) bool {
+	seen := make(map[int]bool, len(nodes))
+	for _, n := range nodes {
+		if seen[n.ID()] {
+			return false
+		}
+		seen[n.ID()] = true
+	}
+	return true
+}
+
+// A plateau is a run of nodes joined by edges which lie on both the forward and backward shortest path trees
+type plateau struct {
+	nodes  []graph.Node
+	length float64
+}
+
+// plateausByLength sorts plateaus in decreasing order of length, then by the ID of their first node
+type plateausByLength []plateau
+
+func (p plateausByLength) Len() int {
+	return len(p)
+}
+
+func (p plateausByLength) Less(i, j int) bool {
+	if p[i].length != p[j].length {
+		return p[i].length > p[j].length
+	}
+	return p[i].nodes[0].ID() < p[j].nodes[0].ID()
+}
+
+func (p plateausByLength) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
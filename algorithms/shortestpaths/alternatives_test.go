@@ -0,0 +1,197 @@
+package shortestpaths
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestAlternatives(t *testing.T) {
+	suite.Run(t, new(AlternativesTestSuite))
+}
+
+type AlternativesTestSuite struct {
+	suite.Suite
+}
+
+func (suite *AlternativesTestSuite) generateGraph(edges []costedEdgePrototype) graph.Graph {
+	g := graph.NewGraph()
+	for _, e := range edges {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: e.srcId},
+			T:    graph.Node{Id: e.targetId},
+			Cost: e.cost,
+		})
+	}
+	return g
+}
+
+func (suite *AlternativesTestSuite) ids(nodes []graph.Node) []int {
+	result := make([]int, len(nodes))
+	for i, n := range nodes {
+		result[i] = n.ID()
+	}
+	return result
+}
+
+// disjointGraph has four disjoint routes from 1 to 10, of costs 6, 7, 9 and 20
+func (suite *AlternativesTestSuite) disjointGraph() graph.Graph {
+	return suite.generateGraph([]costedEdgePrototype{
+		{1, 2, 2}, {2, 3, 2}, {3, 10, 2},
+		{1, 4, 2}, {4, 5, 3}, {5, 10, 2},
+		{1, 7, 3}, {7, 8, 3}, {8, 10, 3},
+		{1, 9, 10}, {9, 10, 10},
+	})
+}
+
+func (suite *AlternativesTestSuite) TestDisjoint() {
+	t := suite.T()
+	g := suite.disjointGraph()
+
+	for _, method := range []AlternativeMethod{PenaltyMethod, PlateauMethod} {
+		opts := DefaultAlternativeOptions()
+		opts.Method = method
+		opts.Penalty = 3 // Enough for each route found to be steered away from until the longest is reached
+		alternatives, err := Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 10}, opts)
+		assert.NoError(t, err)
+		assert.Len(t, alternatives, 3, "method %d", method)
+		assert.Equal(t, []int{1, 2, 3, 10}, suite.ids(alternatives[0].Nodes))
+		assert.Equal(t, []int{1, 4, 5, 10}, suite.ids(alternatives[1].Nodes))
+		assert.Equal(t, []int{1, 7, 8, 10}, suite.ids(alternatives[2].Nodes))
+		for _, a := range alternatives {
+			assert.Equal(t, 0.0, a.Overlap)
+		}
+
+		// Disjoint routes have no overlap at all
+		opts.MaxOverlap = 0
+		alternatives, err = Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 10}, opts)
+		assert.NoError(t, err)
+		assert.Len(t, alternatives, 3, "method %d", method)
+
+		// The route via 7 is too long
+		opts.MaxStretch = 1.2
+		alternatives, err = Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 10}, opts)
+		assert.NoError(t, err)
+		assert.Len(t, alternatives, 2, "method %d", method)
+
+		opts.MaxAlternatives = 4
+		opts.MaxStretch = 4
+		alternatives, err = Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 10}, opts)
+		assert.NoError(t, err)
+		assert.Len(t, alternatives, 4, "method %d", method)
+		assert.Equal(t, 20.0, alternatives[3].Cost)
+	}
+}
+
+func (suite *AlternativesTestSuite) TestOverlap() {
+	t := suite.T()
+	// The only alternative leaves the shortest path at 2
+	g := suite.generateGraph([]costedEdgePrototype{
+		{1, 2, 2}, {2, 3, 2}, {3, 4, 2},
+		{2, 5, 3}, {5, 4, 3},
+	})
+
+	for _, method := range []AlternativeMethod{PenaltyMethod, PlateauMethod} {
+		opts := DefaultAlternativeOptions()
+		opts.Method = method
+		opts.Penalty = 1
+		alternatives, err := Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 4}, opts)
+		assert.NoError(t, err)
+		assert.Len(t, alternatives, 2, "method %d", method)
+		assert.Equal(t, []int{1, 2, 5, 4}, suite.ids(alternatives[1].Nodes))
+		assert.Equal(t, 8.0, alternatives[1].Cost)
+		assert.Equal(t, 0.25, alternatives[1].Overlap)
+
+		opts.MaxOverlap = 0.2
+		alternatives, err = Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 4}, opts)
+		assert.NoError(t, err)
+		assert.Len(t, alternatives, 1, "method %d", method)
+
+		// Zero options are not replaced by defaults
+		opts.MaxOverlap = 0
+		alternatives, err = Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 4}, opts)
+		assert.NoError(t, err)
+		assert.Len(t, alternatives, 1, "method %d", method)
+		opts = DefaultAlternativeOptions()
+		opts.Method = method
+		opts.MaxStretch = 0
+		alternatives, err = Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 4}, opts)
+		assert.NoError(t, err)
+		assert.Len(t, alternatives, 1, "method %d", method)
+	}
+}
+
+func (suite *AlternativesTestSuite) TestErrors() {
+	t := suite.T()
+	g := suite.disjointGraph()
+	g.AddNode(graph.Node{Id: 11})
+
+	_, err := Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 11}, DefaultAlternativeOptions())
+	assert.Equal(t, ErrUnreachable, err)
+
+	opts := DefaultAlternativeOptions()
+	for _, penalty := range []float64{0, -0.5} {
+		opts.Penalty = penalty
+		alternatives, err := Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 10}, opts)
+		assert.Equal(t, ErrInvalidPenalty, err)
+		assert.Nil(t, alternatives)
+	}
+	// The penalty is only used by the penalty method
+	opts.Method = PlateauMethod
+	_, err = Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 10}, opts)
+	assert.NoError(t, err)
+}
+
+func (suite *AlternativesTestSuite) TestPenaltyStopsOnRepeat() {
+	t := suite.T()
+	g := suite.disjointGraph()
+
+	// Once the second route is penalised, the (penalised) shortest route is cheaper than the third, so is found again:
+	// the search then stops, rather than penalising it further to reach the others
+	opts := DefaultAlternativeOptions()
+	opts.Penalty = 0.2
+	opts.MaxAlternatives = 4
+	opts.MaxStretch = 4
+	alternatives, err := Alternatives(g, graph.Node{Id: 1}, graph.Node{Id: 10}, opts)
+	assert.NoError(t, err)
+	assert.Len(t, alternatives, 2)
+}
+
+func (suite *AlternativesTestSuite) TestConstraints() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(16))
+	g := randomGeometricGraph(rng, 300, 1200)
+	nodes := g.NodeList()
+
+	checked := 0
+	for i := 0; i < 10; i++ {
+		s, u := nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))]
+		shortest, err := DijkstraPath(g, s, u)
+		if err != nil {
+			continue
+		}
+		checked++
+
+		for _, method := range []AlternativeMethod{PenaltyMethod, PlateauMethod} {
+			opts := AlternativeOptions{Method: method, MaxAlternatives: 4, MaxOverlap: 0.6, MaxStretch: 1.4, Penalty: 0.5}
+			alternatives, err := Alternatives(g, s, u, opts)
+			assert.NoError(t, err)
+			assert.True(t, len(alternatives) >= 1 && len(alternatives) <= 4)
+			assert.Equal(t, shortest.Cost, alternatives[0].Cost)
+
+			for j, a := range alternatives {
+				assert.True(t, simple(a.Nodes))
+				assert.True(t, a.Cost <= shortest.Cost*opts.MaxStretch+1e-9)
+				assert.True(t, a.Overlap <= opts.MaxOverlap)
+				for _, b := range alternatives[:j] {
+					assert.False(t, nodesEqual(a.Nodes, b.Nodes))
+				}
+			}
+		}
+	}
+	assert.NotZero(t, checked)
+}
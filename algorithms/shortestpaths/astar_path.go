@@ -0,0 +1,79 @@
+package shortestpaths
+
+import (
+	"github.com/obeattie/vrp/graph"
+	"github.com/obeattie/vrp/route"
+)
+
+// A Heuristic estimates the cost of the cheapest path from a node to the target.
+//
+// To guarantee that AStarPath returns an optimal path, the estimate must never exceed the true cost (admissible), and
+// must not decrease by more than the cost of any edge traversed (consistent).
+type Heuristic func(n, target graph.Node) float64
+
+// HaversineHeuristic returns a Heuristic which estimates costs from the great-circle distance between the coordinates
+// of two nodes, travelled at maxSpeed (given in meters per unit of edge cost).
+//
+// The heuristic is admissible as long as no edge can be traversed faster than maxSpeed. For graphs returned by
+// route.Route.Graph (whose edge costs are in milliseconds), maxSpeed should be given in meters per millisecond.
+func HaversineHeuristic(maxSpeed float64) Heuristic {
+	return func(n, target graph.Node) float64 {
+		return route.HaversineInMeters(n.Lat, n.Lng, target.Lat, target.Lng) / maxSpeed
+	}
+}
+
+// AStarPath returns the shortest path from source to target, using heuristic to direct the search towards the target.
+//
+// The result is the same as that of DijkstraPath, but for a good heuristic far fewer nodes are explored. A nil
+// heuristic degrades to Dijkstra's algorithm.
+func AStarPath(g graph.Graph, source, target graph.Node, heuristic Heuristic) (Path, error) {
+	if heuristic == nil {
+		heuristic = func(n, target graph.Node) float64 { return 0 }
+	}
+	if source == target {
+		return NewPath(g, []graph.Node{source})
+	}
+
+	explored := map[graph.Node]bool{}
+	costs := map[graph.Node]float64{ // Best known cost from the source
+		source: 0.0,
+	}
+	parents := map[graph.Node]graph.Node{}
+	fringe := newNodeHeap()
+	fringe.Push(source, heuristic(source, target))
+
+	for fringe.Len() > 0 {
+		v, _ := fringe.Pop()
+		explored[v] = true
+		if v == target {
+			// Walk back up the parents to build the path
+			path := []graph.Node{v}
+			for v != source {
+				v = parents[v]
+				path = append(path, v)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return NewPath(g, path)
+		}
+
+		for _, w := range g.Successors(v) {
+			edge := g.EdgeTo(v, w)
+			if edge.Cost < 0 {
+				return Path{}, ErrContradiction
+			}
+			if explored[w] {
+				continue
+			}
+			vwDist := costs[v] + edge.Cost
+			if wDist, ok := costs[w]; !ok || vwDist < wDist {
+				costs[w] = vwDist
+				parents[w] = v
+				fringe.Push(w, vwDist+heuristic(w, target))
+			}
+		}
+	}
+
+	return Path{}, ErrUnreachable
+}
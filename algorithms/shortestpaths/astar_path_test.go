@@ -0,0 +1,136 @@
+package shortestpaths
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+	"github.com/obeattie/vrp/route"
+)
+
+// Meters per unit of cost used when generating random geometric graphs
+const testMaxSpeed = 10.0
+
+// randomGeometricGraph generates a graph of n nodes scattered around central London, with directed edges between
+// nodes less than radius meters apart. Edge costs are the travel time at no more than testMaxSpeed.
+func randomGeometricGraph(rng *rand.Rand, n int, radius float64) graph.Graph {
+	g := graph.NewGraph()
+	nodes := make([]graph.Node, n)
+	for i := range nodes {
+		nodes[i] = graph.Node{
+			Id:  i + 1,
+			Lat: 51.48 + rng.Float64()*0.06,
+			Lng: -0.17 + rng.Float64()*0.1,
+		}
+		g.AddNode(nodes[i])
+	}
+
+	for _, u := range nodes {
+		for _, v := range nodes {
+			if u == v {
+				continue
+			}
+			meters := route.HaversineInMeters(u.Lat, u.Lng, v.Lat, v.Lng)
+			if meters > radius || rng.Float64() < 0.1 { // Drop some edges so the graph isn't symmetric
+				continue
+			}
+			g.AddDirectedEdge(&graph.Edge{
+				H:    u,
+				T:    v,
+				Cost: meters / testMaxSpeed * (1 + rng.Float64()),
+			})
+		}
+	}
+
+	return g
+}
+
+func TestAStarPath(t *testing.T) {
+	suite.Run(t, new(AStarPathTestSuite))
+}
+
+type AStarPathTestSuite struct {
+	suite.Suite
+}
+
+func (suite *AStarPathTestSuite) generateGraph(nodes []nodePrototype, costs float64) graph.Graph {
+	g := graph.NewGraph()
+
+	for _, n := range nodes {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: n.srcId},
+			T:    graph.Node{Id: n.targetId},
+			Cost: costs,
+		})
+	}
+
+	return g
+}
+
+func (suite *AStarPathTestSuite) TestAStarPath() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+		{3, 4},
+		{4, 5},
+		{4, 6},
+		{5, 7},
+		{6, 7},
+	}, 2)
+
+	path, err := AStarPath(g, graph.Node{Id: 1}, graph.Node{Id: 7}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, path.Nodes, 6)
+	assert.Equal(t, 1, path.Source().ID())
+	assert.Equal(t, 7, path.Target().ID())
+	assert.Equal(t, 10.0, path.Cost)
+
+	path, err = AStarPath(g, graph.Node{Id: 4}, graph.Node{Id: 4}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []graph.Node{{Id: 4}}, path.Nodes)
+	assert.Equal(t, 0.0, path.Cost)
+
+	path, err = AStarPath(g, graph.Node{Id: 7}, graph.Node{Id: 2}, nil)
+	assert.Equal(t, ErrUnreachable, err)
+	assert.True(t, path.IsZero())
+}
+
+func (suite *AStarPathTestSuite) TestNegativeCost() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+	}, -1)
+
+	_, err := AStarPath(g, graph.Node{Id: 1}, graph.Node{Id: 3}, nil)
+	assert.Equal(t, ErrContradiction, err)
+}
+
+func (suite *AStarPathTestSuite) TestMatchesDijkstra() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(1))
+	heuristic := HaversineHeuristic(testMaxSpeed)
+
+	for i := 0; i < 5; i++ {
+		g := randomGeometricGraph(rng, 150, 1200)
+		nodes := g.NodeList()
+
+		for j := 0; j < 20; j++ {
+			source, target := nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))]
+			expected, expectedErr := DijkstraPath(g, source, target)
+			actual, err := AStarPath(g, source, target, heuristic)
+
+			assert.Equal(t, expectedErr, err)
+			if expectedErr != nil {
+				continue
+			}
+			assert.Equal(t, source, actual.Source())
+			assert.Equal(t, target, actual.Target())
+			assert.InEpsilon(t, expected.Cost+1, actual.Cost+1, 1e-6)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package shortestpaths
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// A NegativeCycleError reports a cycle of negative total cost, which makes shortest paths through it undefined. It
+// matches ErrNegativeCycle when compared with errors.Is.
+type NegativeCycleError struct {
+	// Cycle holds the nodes of the cycle in order. The last node has an edge back to the first.
+	Cycle []graph.Node
+}
+
+func (e *NegativeCycleError) Error() string {
+	ids := make([]int, len(e.Cycle))
+	for i, n := range e.Cycle {
+		ids[i] = n.ID()
+	}
+	return fmt.Sprintf("%s: %v", ErrNegativeCycle.Error(), ids)
+}
+
+func (e *NegativeCycleError) Is(target error) bool {
+	return target == ErrNegativeCycle
+}
+
+// BellmanFordPath returns the shortest path from source to target. Unlike DijkstraPath, edges may have negative costs.
+//
+// If a negative-cost cycle is reachable from the source, a *NegativeCycleError is returned.
+func BellmanFordPath(g graph.Graph, source, target graph.Node) (Path, error) {
+	tree, err := BellmanFord(g, source)
+	if err != nil {
+		return Path{}, err
+	}
+	return tree.PathTo(target)
+}
+
+// BellmanFord returns the tree of shortest paths from source to every node reachable from it. Unlike
+// SingleSourceDijkstra, edges may have negative costs.
+//
+// If a negative-cost cycle is reachable from the source, a *NegativeCycleError is returned.
+func BellmanFord(g graph.Graph, source graph.Node) (*ShortestPathTree, error) {
+	nodes := g.NodeList()
+	type edge struct {
+		h, t graph.Node
+		cost float64
+	}
+	edges := make([]edge, 0, len(nodes))
+	for _, n := range nodes {
+		for _, successor := range g.Successors(n) {
+			edges = append(edges, edge{n, successor, g.EdgeTo(n, successor).Cost})
+		}
+	}
+
+	costs := map[graph.Node]float64{
+		source: 0.0,
+	}
+	predecessors := map[graph.Node]graph.Node{}
+	cost := func(n graph.Node) float64 {
+		if c, ok := costs[n]; ok {
+			return c
+		}
+		return math.Inf(0)
+	}
+
+	// Any shortest path has fewer edges than there are nodes, so if costs are still improving after that many rounds of
+	// relaxation, there must be a negative cycle
+	for i := 0; ; i++ {
+		relaxed := -1
+		for j, e := range edges {
+			if hCost := cost(e.h); !math.IsInf(hCost, 1) && hCost+e.cost < cost(e.t) {
+				costs[e.t] = hCost + e.cost
+				predecessors[e.t] = e.h
+				relaxed = j
+			}
+		}
+
+		if relaxed < 0 {
+			return &ShortestPathTree{
+				Source:       source,
+				Costs:        costs,
+				Predecessors: predecessors,
+				g:            g,
+			}, nil
+		} else if i == len(nodes) {
+			return nil, &NegativeCycleError{Cycle: predecessorCycle(predecessors, edges[relaxed].t, len(nodes))}
+		}
+	}
+}
+
+// predecessorCycle returns the cycle in the predecessor graph which n leads into.
+func predecessorCycle(predecessors map[graph.Node]graph.Node, n graph.Node, nodeCount int) []graph.Node {
+	// Walking back through as many predecessors as there are nodes guarantees we're on the cycle
+	for i := 0; i < nodeCount; i++ {
+		n = predecessors[n]
+	}
+
+	cycle := []graph.Node{n}
+	for v := predecessors[n]; v != n; v = predecessors[v] {
+		cycle = append(cycle, v)
+	}
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 { // We walked the cycle backwards
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+	return cycle
+}
@@ -0,0 +1,123 @@
+package shortestpaths
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestBellmanFord(t *testing.T) {
+	suite.Run(t, new(BellmanFordTestSuite))
+}
+
+type BellmanFordTestSuite struct {
+	suite.Suite
+}
+
+type costedEdgePrototype struct {
+	srcId, targetId int
+	cost            float64
+}
+
+func (suite *BellmanFordTestSuite) generateGraph(edges []costedEdgePrototype) graph.Graph {
+	g := graph.NewGraph()
+
+	for _, e := range edges {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: e.srcId},
+			T:    graph.Node{Id: e.targetId},
+			Cost: e.cost,
+		})
+	}
+
+	return g
+}
+
+func (suite *BellmanFordTestSuite) TestNegativeEdges() {
+	t := suite.T()
+	g := suite.generateGraph([]costedEdgePrototype{
+		{1, 2, 4},
+		{1, 3, 5},
+		{2, 4, 3},
+		{3, 2, -3},
+		{4, 5, 2},
+		{3, 5, 6},
+	})
+
+	tree, err := BellmanFord(g, graph.Node{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, map[graph.Node]float64{
+		{Id: 1}: 0,
+		{Id: 2}: 2,
+		{Id: 3}: 5,
+		{Id: 4}: 5,
+		{Id: 5}: 7,
+	}, tree.Costs)
+
+	p, err := BellmanFordPath(g, graph.Node{Id: 1}, graph.Node{Id: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []graph.Node{{Id: 1}, {Id: 3}, {Id: 2}, {Id: 4}, {Id: 5}}, p.Nodes)
+	assert.Equal(t, 7.0, p.Cost)
+
+	_, err = BellmanFordPath(g, graph.Node{Id: 5}, graph.Node{Id: 1})
+	assert.Equal(t, ErrUnreachable, err)
+}
+
+func (suite *BellmanFordTestSuite) TestNegativeCycle() {
+	t := suite.T()
+	g := suite.generateGraph([]costedEdgePrototype{
+		{1, 2, 1},
+		{2, 3, 1},
+		{3, 4, -1},
+		{4, 2, -1},
+		{4, 5, 1},
+	})
+
+	tree, err := BellmanFord(g, graph.Node{Id: 1})
+	assert.Nil(t, tree)
+	assert.True(t, errors.Is(err, ErrNegativeCycle))
+
+	var cycleErr *NegativeCycleError
+	assert.True(t, errors.As(err, &cycleErr))
+	assert.Len(t, cycleErr.Cycle, 3)
+	cycleCost := 0.0
+	for i, n := range cycleErr.Cycle {
+		next := cycleErr.Cycle[(i+1)%len(cycleErr.Cycle)]
+		edge := g.EdgeTo(n, next)
+		if assert.NotNil(t, edge, "Cycle nodes not joined by an edge") {
+			cycleCost += edge.Cost
+		}
+	}
+	assert.True(t, cycleCost < 0)
+
+	// Unreachable cycles don't matter
+	tree, err = BellmanFord(g, graph.Node{Id: 5})
+	assert.NoError(t, err)
+	assert.Len(t, tree.Costs, 1)
+}
+
+func (suite *BellmanFordTestSuite) TestMatchesDijkstra() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(8))
+	g := randomGeometricGraph(rng, 100, 1200)
+	nodes := g.NodeList()
+
+	for i := 0; i < 10; i++ {
+		source := nodes[rng.Intn(len(nodes))]
+		expected, err := SingleSourceDijkstra(g, source, math.Inf(0))
+		assert.NoError(t, err)
+		actual, err := BellmanFord(g, source)
+		assert.NoError(t, err)
+
+		assert.Len(t, actual.Costs, len(expected.Costs))
+		for n, cost := range expected.Costs {
+			assert.InDelta(t, cost, actual.Cost(n), 1e-6)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package shortestpaths
+
+import (
+	"math"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+const (
+	forward = iota
+	backward
+)
+
+// BidirectionalDijkstraPath returns the shortest path from source to target, searching forwards from the source and
+// backwards from the target simultaneously.
+//
+// The result is the same as that of DijkstraPath, but as the two searches meet roughly half-way, far fewer nodes are
+// explored on most graphs.
+func BidirectionalDijkstraPath(g graph.Graph, source, target graph.Node) (Path, error) {
+	if source == target {
+		return NewPath(g, []graph.Node{source})
+	}
+
+	costs := [2]map[graph.Node]float64{{}, {}}      // Final costs from the source and to the target
+	parents := [2]map[graph.Node]graph.Node{{}, {}} // Next node towards the source or target respectively
+	fringes := [2]*nodeHeap{newNodeHeap(), newNodeHeap()}
+	fringes[forward].Push(source, 0)
+	fringes[backward].Push(target, 0)
+	// The best known cost of a node from the source or to the target, whether final or still queued
+	tentative := func(dir int, n graph.Node) (float64, bool) {
+		if cost, ok := costs[dir][n]; ok {
+			return cost, true
+		}
+		return fringes[dir].Priority(n)
+	}
+
+	// The best path found so far passes through meet, at a total cost of bestCost
+	var meet graph.Node
+	bestCost := math.Inf(0)
+	found := false
+
+	for dir := forward; fringes[forward].Len() > 0 && fringes[backward].Len() > 0; dir = 1 - dir {
+		v, vDist := fringes[dir].Pop()
+		costs[dir][v] = vDist
+		if _, ok := costs[1-dir][v]; ok { // Searched from both directions; no shorter path can exist
+			break
+		}
+
+		var neighbours []graph.Node
+		if dir == forward {
+			neighbours = g.Successors(v)
+		} else {
+			neighbours = g.Predecessors(v)
+		}
+		for _, w := range neighbours {
+			var edge *graph.Edge
+			if dir == forward {
+				edge = g.EdgeTo(v, w)
+			} else {
+				edge = g.EdgeTo(w, v)
+			}
+			vwDist := vDist + edge.Cost
+			if wDist, ok := costs[dir][w]; ok {
+				if vwDist < wDist {
+					return Path{}, ErrContradiction
+				}
+			} else if wSeen, ok := fringes[dir].Priority(w); !ok || vwDist < wSeen {
+				fringes[dir].Push(w, vwDist)
+				parents[dir][w] = v
+				if otherDist, ok := tentative(1-dir, w); ok && vwDist+otherDist < bestCost {
+					meet, bestCost, found = w, vwDist+otherDist, true
+				}
+			}
+		}
+	}
+
+	if !found {
+		return Path{}, ErrUnreachable
+	}
+
+	// Walk from the meeting point back to the source, then on to the target
+	path := []graph.Node{meet}
+	for n := meet; n != source; {
+		n = parents[forward][n]
+		path = append(path, n)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	for n := meet; n != target; {
+		n = parents[backward][n]
+		path = append(path, n)
+	}
+	return NewPath(g, path)
+}
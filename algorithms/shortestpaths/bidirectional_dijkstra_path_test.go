@@ -0,0 +1,119 @@
+package shortestpaths
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestBidirectionalDijkstraPath(t *testing.T) {
+	suite.Run(t, new(BidirectionalDijkstraPathTestSuite))
+}
+
+type BidirectionalDijkstraPathTestSuite struct {
+	suite.Suite
+}
+
+func (suite *BidirectionalDijkstraPathTestSuite) generateGraph(nodes []nodePrototype, costs float64) graph.Graph {
+	g := graph.NewGraph()
+
+	for _, n := range nodes {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: n.srcId},
+			T:    graph.Node{Id: n.targetId},
+			Cost: costs,
+		})
+	}
+
+	return g
+}
+
+func (suite *BidirectionalDijkstraPathTestSuite) TestBidirectionalDijkstraPath() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+		{3, 4},
+		{4, 5},
+		{4, 6},
+		{5, 7},
+		{6, 7},
+	}, 2)
+
+	shortestPaths := map[[2]int][][]int{
+		[2]int{1, 1}: {{1}},
+		[2]int{1, 2}: {{1, 2}},
+		[2]int{1, 3}: {{1, 2, 3}},
+		[2]int{1, 4}: {{1, 2, 3, 4}},
+		[2]int{1, 5}: {{1, 2, 3, 4, 5}},
+		[2]int{1, 6}: {{1, 2, 3, 4, 6}},
+		[2]int{1, 7}: {{1, 2, 3, 4, 5, 7},
+			{1, 2, 3, 4, 6, 7}},
+		[2]int{4, 7}: {{4, 5, 7},
+			{4, 6, 7}},
+		[2]int{2, 1}: nil,
+		[2]int{7, 2}: nil,
+	}
+	for _origindest, validPaths := range shortestPaths {
+		origin, dest := _origindest[0], _origindest[1]
+
+		returnedPath, err := BidirectionalDijkstraPath(g, graph.Node{Id: origin}, graph.Node{Id: dest})
+
+		if validPaths == nil {
+			assert.Equal(t, ErrUnreachable, err)
+			assert.True(t, returnedPath.IsZero())
+			continue
+		}
+
+		assert.NoError(t, err, "Error retrieving path")
+		returnedIds := make([]int, len(returnedPath.Nodes))
+		for i, n := range returnedPath.Nodes {
+			returnedIds[i] = n.ID()
+		}
+		assert.Contains(t, validPaths, returnedIds, "Valid path from %d -> %d not returned", origin, dest)
+	}
+}
+
+func (suite *BidirectionalDijkstraPathTestSuite) TestNegativeCost() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{3, 4},
+		{4, 5},
+		{5, 6},
+		{6, 7},
+	}, 2)
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 1}, T: graph.Node{Id: 3}, Cost: 1})
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 2}, T: graph.Node{Id: 3}, Cost: -5})
+
+	_, err := BidirectionalDijkstraPath(g, graph.Node{Id: 1}, graph.Node{Id: 7})
+	assert.Equal(t, ErrContradiction, err)
+}
+
+func (suite *BidirectionalDijkstraPathTestSuite) TestMatchesDijkstra() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(2))
+
+	for i := 0; i < 5; i++ {
+		g := randomGeometricGraph(rng, 150, 1200)
+		nodes := g.NodeList()
+
+		for j := 0; j < 20; j++ {
+			source, target := nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))]
+			expected, expectedErr := DijkstraPath(g, source, target)
+			actual, err := BidirectionalDijkstraPath(g, source, target)
+
+			assert.Equal(t, expectedErr, err)
+			if expectedErr != nil {
+				continue
+			}
+			assert.Equal(t, source, actual.Source())
+			assert.Equal(t, target, actual.Target())
+			assert.InEpsilon(t, expected.Cost+1, actual.Cost+1, 1e-6)
+		}
+	}
+}
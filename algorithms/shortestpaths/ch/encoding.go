@@ -0,0 +1,164 @@
+package ch
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+var ErrInvalidEncoding = errors.New("Invalid contraction hierarchy encoding")
+
+// The serialised form of a Hierarchy
+type encodedHierarchy struct {
+	Nodes    []graph.Node
+	Up, Down [][]encodedArc
+}
+
+type encodedArc struct {
+	Node, Middle int
+	Cost         float64
+	Edge         *graph.Edge // Only for original edges, so paths keep their capacities
+}
+
+func encodeArcs(arcs [][]arc) [][]encodedArc {
+	result := make([][]encodedArc, len(arcs))
+	for v, vArcs := range arcs {
+		result[v] = make([]encodedArc, len(vArcs))
+		for i, a := range vArcs {
+			result[v][i] = encodedArc{Node: a.node, Middle: a.middle, Cost: a.cost, Edge: a.edge}
+		}
+	}
+	return result
+}
+
+func decodeArcs(encoded [][]encodedArc) [][]arc {
+	result := make([][]arc, len(encoded))
+	for v, vArcs := range encoded {
+		result[v] = make([]arc, len(vArcs))
+		for i, a := range vArcs {
+			result[v][i] = arc{node: a.Node, middle: a.Middle, cost: a.Cost, edge: a.Edge}
+		}
+	}
+	return result
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, so a Hierarchy may be precomputed and stored.
+func (h *Hierarchy) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := gob.NewEncoder(buf).Encode(encodedHierarchy{
+		Nodes: h.nodes,
+		Up:    encodeArcs(h.up),
+		Down:  encodeArcs(h.down),
+	})
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, restoring a Hierarchy stored by MarshalBinary.
+func (h *Hierarchy) UnmarshalBinary(data []byte) error {
+	var encoded encodedHierarchy
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&encoded); err != nil {
+		return err
+	}
+
+	index := make(map[int]int, len(encoded.Nodes))
+	for i, n := range encoded.Nodes {
+		if _, ok := index[n.ID()]; ok {
+			return ErrInvalidEncoding
+		}
+		index[n.ID()] = i
+	}
+	up, down := decodeArcs(encoded.Up), decodeArcs(encoded.Down)
+	if err := validate(encoded.Nodes, up, down); err != nil {
+		return err
+	}
+
+	h.nodes = encoded.Nodes
+	h.index = index
+	h.up = up
+	h.down = down
+	return nil
+}
+
+// validate checks that arcs decoded from untrusted input form a hierarchy which can be queried (and its shortcuts
+// unpacked) without panicking or recursing forever.
+func validate(nodes []graph.Node, up, down [][]arc) error {
+	n := len(nodes)
+	if len(up) != n || len(down) != n {
+		return ErrInvalidEncoding
+	}
+
+	// Every arc is between two distinct nodes. An original edge joins those nodes, and a shortcut bypasses a third
+	// node whose arcs to each end exist
+	exists := make(map[[2]int]bool)
+	for u := range up {
+		for _, a := range up[u] {
+			exists[[2]int{u, a.node}] = true
+		}
+		for _, a := range down[u] {
+			exists[[2]int{a.node, u}] = true
+		}
+	}
+	// above[v] holds the nodes which must have a higher rank than v: up arcs lead to nodes of higher rank, down arcs
+	// come from them, and shortcuts only ever bypass nodes of lower rank than both ends
+	above := make([][]int, n)
+	check := func(u, w int, a arc) bool {
+		middle := a.middle
+		if u < 0 || u >= n || w < 0 || w >= n || u == w {
+			return false
+		}
+		if middle == -1 {
+			return a.edge != nil && a.edge.H.ID() == nodes[u].ID() && a.edge.T.ID() == nodes[w].ID()
+		}
+		if a.edge != nil || middle < 0 || middle >= n || middle == u || middle == w ||
+			!exists[[2]int{u, middle}] || !exists[[2]int{middle, w}] {
+			return false
+		}
+		above[middle] = append(above[middle], u, w)
+		return true
+	}
+	for v := range up {
+		for _, a := range up[v] {
+			if !check(v, a.node, a) {
+				return ErrInvalidEncoding
+			}
+			above[v] = append(above[v], a.node)
+		}
+		for _, a := range down[v] {
+			if !check(a.node, v, a) {
+				return ErrInvalidEncoding
+			}
+			above[v] = append(above[v], a.node)
+		}
+	}
+
+	// Those constraints must be consistent with some ranking, so they can contain no cycle
+	inDegree := make([]int, n)
+	for _, higher := range above {
+		for _, w := range higher {
+			inDegree[w]++
+		}
+	}
+	toVisit := make([]int, 0, n)
+	for v, d := range inDegree {
+		if d == 0 {
+			toVisit = append(toVisit, v)
+		}
+	}
+	ranked := 0
+	for len(toVisit) > 0 {
+		v := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		ranked++
+		for _, w := range above[v] {
+			if inDegree[w]--; inDegree[w] == 0 {
+				toVisit = append(toVisit, w)
+			}
+		}
+	}
+	if ranked != n {
+		return ErrInvalidEncoding
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package ch
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestEncodingRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	g := randomGraph(rng, 200)
+	h, err := New(g)
+	assert.NoError(t, err)
+
+	data, err := h.MarshalBinary()
+	assert.NoError(t, err)
+	decoded := new(Hierarchy)
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+
+	nodes := g.NodeList()
+	for i := 0; i < 50; i++ {
+		source, target := nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))]
+		expected, expectedErr := h.Path(source, target)
+		actual, err := decoded.Path(source, target)
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	assert.Error(t, new(Hierarchy).UnmarshalBinary([]byte("not a hierarchy")))
+}
+
+func TestUnmarshalCorrupt(t *testing.T) {
+	edge := func(h, t int) *graph.Edge {
+		return &graph.Edge{H: graph.Node{Id: h}, T: graph.Node{Id: t}}
+	}
+	nodes := []graph.Node{{Id: 1}, {Id: 2}, {Id: 3}}
+	cases := map[string]encodedHierarchy{
+		"duplicate nodes": {
+			Nodes: []graph.Node{{Id: 1}, {Id: 1}},
+			Up:    make([][]encodedArc, 2),
+			Down:  make([][]encodedArc, 2),
+		},
+		"too few up arcs": {
+			Nodes: nodes,
+			Up:    make([][]encodedArc, 2),
+			Down:  make([][]encodedArc, 3),
+		},
+		"too many down arcs": {
+			Nodes: nodes,
+			Up:    make([][]encodedArc, 3),
+			Down:  make([][]encodedArc, 4),
+		},
+		"node out of range": {
+			Nodes: nodes,
+			Up:    [][]encodedArc{{{Node: 3, Middle: -1}}, nil, nil},
+			Down:  make([][]encodedArc, 3),
+		},
+		"loop": {
+			Nodes: nodes,
+			Up:    [][]encodedArc{{{Node: 0, Middle: -1}}, nil, nil},
+			Down:  make([][]encodedArc, 3),
+		},
+		"middle out of range": {
+			Nodes: nodes,
+			Up:    [][]encodedArc{{{Node: 1, Middle: 5}}, nil, nil},
+			Down:  make([][]encodedArc, 3),
+		},
+		"middle is an endpoint": {
+			Nodes: nodes,
+			Up:    [][]encodedArc{{{Node: 1, Middle: 1}}, nil, nil},
+			Down:  make([][]encodedArc, 3),
+		},
+		"middle without arcs": {
+			Nodes: nodes,
+			Up:    [][]encodedArc{{{Node: 1, Middle: 2}}, nil, nil},
+			Down:  make([][]encodedArc, 3),
+		},
+		"original edge missing": {
+			Nodes: nodes,
+			Up:    [][]encodedArc{{{Node: 1, Middle: -1}}, nil, nil},
+			Down:  make([][]encodedArc, 3),
+		},
+		"original edge between other nodes": {
+			Nodes: nodes,
+			Up:    [][]encodedArc{{{Node: 1, Middle: -1, Edge: edge(3, 2)}}, nil, nil},
+			Down:  make([][]encodedArc, 3),
+		},
+		"inconsistent ranks": {
+			Nodes: nodes,
+			Up: [][]encodedArc{
+				{{Node: 1, Middle: -1, Edge: edge(1, 2)}},
+				{{Node: 0, Middle: -1, Edge: edge(2, 1)}},
+				nil,
+			},
+			Down: make([][]encodedArc, 3),
+		},
+		"shortcuts bypassing each other": {
+			// 0→1 bypasses 2 and 0→2 bypasses 1, so unpacking either would never finish
+			Nodes: nodes,
+			Up: [][]encodedArc{
+				{{Node: 1, Middle: 2}, {Node: 2, Middle: 1}},
+				{{Node: 2, Middle: -1, Edge: edge(2, 3)}},
+				nil,
+			},
+			Down: [][]encodedArc{nil, {{Node: 2, Middle: -1, Edge: edge(3, 2)}}, nil},
+		},
+	}
+
+	for name, encoded := range cases {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, gob.NewEncoder(buf).Encode(encoded))
+		h := new(Hierarchy)
+		assert.Equal(t, ErrInvalidEncoding, h.UnmarshalBinary(buf.Bytes()), name)
+		assert.Nil(t, h.nodes, name)
+	}
+}
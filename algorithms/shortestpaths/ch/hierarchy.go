@@ -0,0 +1,214 @@
+// Package ch implements contraction hierarchies: a preprocessing step which augments a static graph with shortcut
+// edges, after which shortest path queries only need to explore a tiny portion of the graph.
+package ch
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/algorithms/shortestpaths/internal/pq"
+	"github.com/obeattie/vrp/graph"
+)
+
+// The maximum number of nodes settled by each witness search during contraction. Lower values speed up preprocessing
+// at the expense of (unnecessary) extra shortcuts.
+const maxWitnessSettled = 500
+
+// An arc is an edge in the hierarchy: either an edge in the original graph, or a shortcut which bypasses a contracted
+// node (middle).
+type arc struct {
+	node   int // The node at the other end of the arc
+	cost   float64
+	middle int         // The contracted node a shortcut bypasses, or -1 for original edges
+	edge   *graph.Edge // The edge in the original graph, or nil for shortcuts
+}
+
+// A Hierarchy is a contracted graph, which can answer shortest path queries far faster than searching the original
+// graph. A Hierarchy is immutable and may be queried concurrently.
+type Hierarchy struct {
+	nodes []graph.Node
+	index map[int]int // Node ID -> index within nodes
+	// up[v] holds arcs from v to nodes of higher rank, and down[v] holds arcs to v from nodes of higher rank: all
+	// searches only ever move upwards through the hierarchy.
+	up, down [][]arc
+}
+
+// New contracts the given graph into a Hierarchy. Changes to the graph after the Hierarchy is built are not reflected
+// in its results.
+//
+// Contraction hierarchies cannot represent negative-cost edges; if any are found, ErrContradiction is returned.
+func New(g graph.Graph) (*Hierarchy, error) {
+	nodes := g.NodeList()
+	sort.Sort(graph.NodesById(nodes)) // Determinism
+	index := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		index[n.ID()] = i
+	}
+
+	c := newContractor(len(nodes))
+	for i, u := range nodes {
+		for _, v := range g.Successors(u) {
+			if v.ID() == u.ID() { // Loops can never be part of a shortest path
+				continue
+			}
+			edge := g.EdgeTo(u, v)
+			if edge.Cost < 0 {
+				return nil, shortestpaths.ErrContradiction
+			}
+			c.addArc(i, index[v.ID()], edge.Cost, -1, edge)
+		}
+	}
+	rank := c.contract()
+
+	h := &Hierarchy{
+		nodes: nodes,
+		index: index,
+		up:    make([][]arc, len(nodes)),
+		down:  make([][]arc, len(nodes)),
+	}
+	for u, arcs := range c.arcs {
+		for w, a := range arcs {
+			if rank[w] > rank[u] {
+				h.up[u] = append(h.up[u], arc{node: w, cost: a.cost, middle: a.middle, edge: a.edge})
+			} else {
+				h.down[w] = append(h.down[w], arc{node: u, cost: a.cost, middle: a.middle, edge: a.edge})
+			}
+		}
+	}
+	return h, nil
+}
+
+// contractor holds the state of the hierarchy during its construction.
+type contractor struct {
+	arcs       []map[int]arc     // Every arc (original and shortcut) in the hierarchy, by tail then head
+	out, in    []map[int]float64 // Arcs between uncontracted nodes only
+	contracted []bool
+	deleted    []int // The number of contracted neighbours of each node
+}
+
+func newContractor(n int) *contractor {
+	c := &contractor{
+		arcs:       make([]map[int]arc, n),
+		out:        make([]map[int]float64, n),
+		in:         make([]map[int]float64, n),
+		contracted: make([]bool, n),
+		deleted:    make([]int, n),
+	}
+	for i := 0; i < n; i++ {
+		c.arcs[i] = map[int]arc{}
+		c.out[i] = map[int]float64{}
+		c.in[i] = map[int]float64{}
+	}
+	return c
+}
+
+// addArc adds an arc from u to w, unless a cheaper one already exists.
+func (c *contractor) addArc(u, w int, cost float64, middle int, edge *graph.Edge) {
+	if existing, ok := c.arcs[u][w]; ok && existing.cost <= cost {
+		return
+	}
+	c.arcs[u][w] = arc{node: w, cost: cost, middle: middle, edge: edge}
+	c.out[u][w] = cost
+	c.in[w][u] = cost
+}
+
+// contract contracts every node in order of importance, returning the rank of each.
+func (c *contractor) contract() []int {
+	rank := make([]int, len(c.contracted))
+	fringe := make(pq.Queue, 0, len(c.contracted))
+	for v := range c.contracted {
+		fringe = append(fringe, pq.Item{Node: v, Priority: c.priority(v)})
+	}
+	heap.Init(&fringe)
+
+	for next := 0; fringe.Len() > 0; {
+		v := heap.Pop(&fringe).(pq.Item).Node
+		// Priorities are updated lazily: if v has become more important than the next node, put it back
+		if p := c.priority(v); fringe.Len() > 0 && p > fringe[0].Priority {
+			heap.Push(&fringe, pq.Item{Node: v, Priority: p})
+			continue
+		}
+
+		c.shortcuts(v, true)
+		c.contracted[v] = true
+		rank[v] = next
+		next++
+		for u := range c.in[v] {
+			delete(c.out[u], v)
+			c.deleted[u]++
+		}
+		for w := range c.out[v] {
+			delete(c.in[w], v)
+			c.deleted[w]++
+		}
+	}
+
+	return rank
+}
+
+// priority returns the importance of a node: nodes with lower priorities are contracted first.
+func (c *contractor) priority(v int) float64 {
+	edgeDifference := c.shortcuts(v, false) - len(c.in[v]) - len(c.out[v])
+	return float64(edgeDifference + c.deleted[v])
+}
+
+// shortcuts returns the number of shortcuts which contracting v requires, adding them if apply is true.
+func (c *contractor) shortcuts(v int, apply bool) int {
+	count := 0
+	for u, uvCost := range c.in[v] {
+		maxCost := 0.0
+		for w, vwCost := range c.out[v] {
+			if w != u && uvCost+vwCost > maxCost {
+				maxCost = uvCost + vwCost
+			}
+		}
+		witnesses := c.witnessSearch(u, v, maxCost)
+
+		for w, vwCost := range c.out[v] {
+			if w == u {
+				continue
+			}
+			if cost, ok := witnesses[w]; ok && cost <= uvCost+vwCost {
+				continue
+			}
+			count++
+			if apply {
+				c.addArc(u, w, uvCost+vwCost, v, nil)
+			}
+		}
+	}
+	return count
+}
+
+// witnessSearch returns the costs of paths from source which avoid the given node, exploring no further than maxCost.
+func (c *contractor) witnessSearch(source, avoid int, maxCost float64) map[int]float64 {
+	costs := map[int]float64{}
+	seen := map[int]float64{source: 0}
+	fringe := pq.Queue{{Node: source}}
+
+	for fringe.Len() > 0 && len(costs) < maxWitnessSettled {
+		item := heap.Pop(&fringe).(pq.Item)
+		v := item.Node
+		if _, ok := costs[v]; ok {
+			continue
+		}
+		costs[v] = item.Priority
+		if item.Priority > maxCost {
+			break
+		}
+
+		for w, vwCost := range c.out[v] {
+			if w == avoid {
+				continue
+			}
+			vwDist := item.Priority + vwCost
+			if wSeen, ok := seen[w]; !ok || vwDist < wSeen {
+				seen[w] = vwDist
+				heap.Push(&fringe, pq.Item{Node: w, Priority: vwDist})
+			}
+		}
+	}
+
+	return costs
+}
@@ -0,0 +1,182 @@
+package ch
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/graph"
+)
+
+type nodePrototype struct {
+	srcId, targetId int
+}
+
+// randomGraph generates a sparse graph of n nodes, each with a handful of edges to nearby nodes of random cost and
+// capacity
+func randomGraph(rng *rand.Rand, n int) graph.Graph {
+	g := graph.NewGraph()
+	for i := 1; i <= n; i++ {
+		g.AddNode(graph.Node{Id: i})
+	}
+	for i := 1; i <= n; i++ {
+		for j := 0; j < 3; j++ {
+			target := i + rng.Intn(21) - 10
+			if target < 1 || target > n || target == i {
+				continue
+			}
+			g.AddDirectedEdge(&graph.Edge{
+				H:        graph.Node{Id: i},
+				T:        graph.Node{Id: target},
+				Cost:     float64(1 + rng.Intn(100)),
+				Capacity: float64(rng.Intn(100)),
+			})
+		}
+	}
+	return g
+}
+
+func TestHierarchy(t *testing.T) {
+	suite.Run(t, new(HierarchyTestSuite))
+}
+
+type HierarchyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *HierarchyTestSuite) generateGraph(nodes []nodePrototype, costs float64) graph.Graph {
+	g := graph.NewGraph()
+
+	for _, n := range nodes {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: n.srcId},
+			T:    graph.Node{Id: n.targetId},
+			Cost: costs,
+		})
+	}
+
+	return g
+}
+
+func (suite *HierarchyTestSuite) TestPath() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+		{3, 4},
+		{4, 5},
+		{4, 6},
+		{5, 7},
+		{6, 7},
+	}, 2)
+	h, err := New(g)
+	assert.NoError(t, err)
+
+	shortestPaths := map[[2]int][][]int{
+		[2]int{1, 1}: {{1}},
+		[2]int{1, 2}: {{1, 2}},
+		[2]int{1, 3}: {{1, 2, 3}},
+		[2]int{1, 4}: {{1, 2, 3, 4}},
+		[2]int{1, 5}: {{1, 2, 3, 4, 5}},
+		[2]int{1, 6}: {{1, 2, 3, 4, 6}},
+		[2]int{1, 7}: {{1, 2, 3, 4, 5, 7},
+			{1, 2, 3, 4, 6, 7}},
+		[2]int{4, 7}: {{4, 5, 7},
+			{4, 6, 7}},
+		[2]int{2, 1}: nil,
+		[2]int{7, 2}: nil,
+		[2]int{1, 8}: nil,
+	}
+	for _origindest, validPaths := range shortestPaths {
+		origin, dest := _origindest[0], _origindest[1]
+
+		returnedPath, err := h.Path(graph.Node{Id: origin}, graph.Node{Id: dest})
+		if validPaths == nil {
+			assert.Equal(t, shortestpaths.ErrUnreachable, err)
+			assert.True(t, returnedPath.IsZero())
+			continue
+		}
+
+		assert.NoError(t, err, "Error retrieving path")
+		returnedIds := make([]int, len(returnedPath.Nodes))
+		for i, n := range returnedPath.Nodes {
+			returnedIds[i] = n.ID()
+		}
+		assert.Contains(t, validPaths, returnedIds, "Valid path from %d -> %d not returned", origin, dest)
+
+		cost, err := h.Cost(graph.Node{Id: origin}, graph.Node{Id: dest})
+		assert.NoError(t, err)
+		assert.Equal(t, float64((len(returnedIds)-1)*2), cost)
+		assert.Equal(t, returnedPath.Cost, cost)
+		assert.Len(t, returnedPath.Edges, returnedPath.Len())
+		assert.Equal(t, len(returnedIds)-1, returnedPath.Len())
+	}
+}
+
+func (suite *HierarchyTestSuite) TestOriginalEdges() {
+	t := suite.T()
+	g := graph.NewGraph()
+	for i := 1; i < 5; i++ {
+		g.AddDirectedEdge(&graph.Edge{
+			H:        graph.Node{Id: i},
+			T:        graph.Node{Id: i + 1},
+			Cost:     1,
+			Capacity: float64(10 * i),
+		})
+	}
+	h, err := New(g)
+	assert.NoError(t, err)
+
+	// However many shortcuts the path was found through, its edges are those of the graph
+	path, err := h.Path(graph.Node{Id: 1}, graph.Node{Id: 5})
+	assert.NoError(t, err)
+	if assert.Len(t, path.Edges, 4) {
+		for i, edge := range path.Edges {
+			assert.Equal(t, *g.EdgeTo(graph.Node{Id: i + 1}, graph.Node{Id: i + 2}), *edge)
+			assert.Equal(t, float64(10*(i+1)), edge.Capacity)
+		}
+	}
+}
+
+func (suite *HierarchyTestSuite) TestNegativeCost() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+	}, -1)
+
+	h, err := New(g)
+	assert.Equal(t, shortestpaths.ErrContradiction, err)
+	assert.Nil(t, h)
+}
+
+func (suite *HierarchyTestSuite) TestMatchesDijkstra() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 5; i++ {
+		g := randomGraph(rng, 300)
+		h, err := New(g)
+		assert.NoError(t, err)
+		nodes := g.NodeList()
+
+		for j := 0; j < 50; j++ {
+			source, target := nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))]
+			expected, expectedErr := shortestpaths.DijkstraPath(g, source, target)
+			actual, err := h.Path(source, target)
+
+			assert.Equal(t, expectedErr, err)
+			if expectedErr != nil {
+				continue
+			}
+			assert.Equal(t, source, actual.Source())
+			assert.Equal(t, target, actual.Target())
+			assert.Equal(t, expected.Cost, actual.Cost)
+			for i, edge := range actual.Edges {
+				assert.Equal(t, *g.EdgeTo(actual.Nodes[i], actual.Nodes[i+1]), *edge)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package ch
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/algorithms/shortestpaths/internal/pq"
+	"github.com/obeattie/vrp/graph"
+)
+
+const (
+	forward = iota
+	backward
+)
+
+// Path returns the shortest path from source to target, as DijkstraPath would in the original graph.
+func (h *Hierarchy) Path(source, target graph.Node) (shortestpaths.Path, error) {
+	s, t, err := h.endpoints(source, target)
+	if err != nil {
+		return shortestpaths.Path{}, err
+	}
+	meet, _, parents := h.search(s, t)
+	if meet < 0 {
+		return shortestpaths.Path{}, shortestpaths.ErrUnreachable
+	}
+
+	// Build the path through the hierarchy (made up of arcs which may be shortcuts)
+	hierarchyPath := []int{meet}
+	for v := meet; v != s; {
+		v = parents[forward][v]
+		hierarchyPath = append(hierarchyPath, v)
+	}
+	for i, j := 0, len(hierarchyPath)-1; i < j; i, j = i+1, j-1 {
+		hierarchyPath[i], hierarchyPath[j] = hierarchyPath[j], hierarchyPath[i]
+	}
+	for v := meet; v != t; {
+		v = parents[backward][v]
+		hierarchyPath = append(hierarchyPath, v)
+	}
+
+	// Unpack it
+	path := shortestpaths.Path{
+		Nodes: []graph.Node{h.nodes[s]},
+	}
+	for i := 1; i < len(hierarchyPath); i++ {
+		h.unpack(hierarchyPath[i-1], hierarchyPath[i], &path)
+	}
+	return path, nil
+}
+
+// Cost returns the cost of the shortest path from source to target.
+func (h *Hierarchy) Cost(source, target graph.Node) (float64, error) {
+	s, t, err := h.endpoints(source, target)
+	if err != nil {
+		return math.Inf(0), err
+	}
+	meet, cost, _ := h.search(s, t)
+	if meet < 0 {
+		return math.Inf(0), shortestpaths.ErrUnreachable
+	}
+	return cost, nil
+}
+
+func (h *Hierarchy) endpoints(source, target graph.Node) (int, int, error) {
+	s, sOk := h.index[source.ID()]
+	t, tOk := h.index[target.ID()]
+	if !sOk || !tOk {
+		return -1, -1, shortestpaths.ErrUnreachable
+	}
+	return s, t, nil
+}
+
+// search performs a bidirectional search upwards through the hierarchy from s and t, returning the node at which the
+// searches met on the shortest path (or -1 if there is no path), the cost of the path, and the parents of each node
+// visited in each direction.
+func (h *Hierarchy) search(s, t int) (int, float64, [2]map[int]int) {
+	arcs := [2][][]arc{h.up, h.down}
+	costs := [2]map[int]float64{{}, {}}
+	seen := [2]map[int]float64{{s: 0}, {t: 0}}
+	parents := [2]map[int]int{{}, {}}
+	fringes := [2]*pq.Queue{{{Node: s}}, {{Node: t}}}
+
+	meet, bestCost := -1, math.Inf(0)
+	for {
+		// Search in whichever direction has the cheaper node next. Each direction is finished once its next node is no
+		// cheaper than the best path found
+		dir := -1
+		for d := forward; d <= backward; d++ {
+			if fringes[d].Len() > 0 && (*fringes[d])[0].Priority < bestCost &&
+				(dir < 0 || (*fringes[d])[0].Priority < (*fringes[dir])[0].Priority) {
+				dir = d
+			}
+		}
+		if dir < 0 {
+			break
+		}
+
+		item := heap.Pop(fringes[dir]).(pq.Item)
+		v := item.Node
+		if _, ok := costs[dir][v]; ok { // Already searched this node
+			continue
+		}
+		costs[dir][v] = item.Priority
+		if otherCost, ok := seen[1-dir][v]; ok && item.Priority+otherCost < bestCost {
+			meet, bestCost = v, item.Priority+otherCost
+		}
+
+		for _, a := range arcs[dir][v] {
+			vwDist := item.Priority + a.cost
+			if wSeen, ok := seen[dir][a.node]; !ok || vwDist < wSeen {
+				seen[dir][a.node] = vwDist
+				parents[dir][a.node] = v
+				heap.Push(fringes[dir], pq.Item{Node: a.node, Priority: vwDist})
+			}
+		}
+	}
+
+	return meet, bestCost, parents
+}
+
+// unpack appends the edges along the (possibly shortcut) arc from u to w to path.
+func (h *Hierarchy) unpack(u, w int, path *shortestpaths.Path) {
+	a := h.arc(u, w)
+	if a.middle >= 0 {
+		h.unpack(u, a.middle, path)
+		h.unpack(a.middle, w, path)
+		return
+	}
+	path.Nodes = append(path.Nodes, h.nodes[w])
+	path.Edges = append(path.Edges, a.edge)
+	path.Cost += a.cost
+}
+
+// arc returns the arc from u to w.
+func (h *Hierarchy) arc(u, w int) arc {
+	for _, a := range h.up[u] {
+		if a.node == w {
+			return a
+		}
+	}
+	for _, a := range h.down[w] {
+		if a.node == u {
+			return a
+		}
+	}
+	panic("ch: arc missing from hierarchy")
+}
@@ -1,82 +1,106 @@
 package shortestpaths
 
 import (
+	"context"
 	"errors"
 	"math"
 
-	"github.com/oleiade/lane"
-
 	"github.com/obeattie/vrp/graph"
 )
 
-const priorityExponent = 100000.0
+// The number of nodes a search explores between checks for cancellation
+const cancellationInterval = 256
 
 var (
 	ErrUnreachable   = errors.New("Unreachable node")
 	ErrContradiction = errors.New("Contradictory graph. Negative-cost edges?")
+	ErrNegativeCycle = errors.New("Graph contains a negative-cost cycle")
 )
 
 // DijkstraPath returns the shortest path from source to target.
-func DijkstraPath(g graph.Graph, source, target graph.Node) ([]graph.Node, error) {
-	paths, _, err := singleSourceDijkstra(g, source, target, math.Inf(0))
+func DijkstraPath(g graph.Graph, source, target graph.Node) (Path, error) {
+	return DijkstraPathContext(context.Background(), g, source, target)
+}
+
+// DijkstraPathContext is like DijkstraPath, but stops searching (returning ctx.Err()) once the context is done.
+func DijkstraPathContext(ctx context.Context, g graph.Graph, source, target graph.Node) (Path, error) {
+	tree, err := singleSourceDijkstra(ctx, g, source, target, math.Inf(0))
 	if err != nil {
-		return nil, err
-	} else if path, ok := paths[target]; ok {
-		return path, nil
-	} else {
-		return nil, ErrUnreachable
+		return Path{}, err
 	}
+	return tree.PathTo(target)
 }
 
-func singleSourceDijkstra(g graph.Graph, source, target graph.Node, cutoff float64) (map[graph.Node][]graph.Node, map[graph.Node]float64, error) {
-	if source == target {
-		paths := map[graph.Node][]graph.Node{
-			source: {source},
-		}
-		costs := map[graph.Node]float64{
-			source: 0,
-		}
-		return paths, costs, nil
+// SingleSourceDijkstra returns the tree of shortest paths from source to every node which can be reached at a cost of
+// no more than cutoff. Pass an infinite cutoff (math.Inf(0)) to search the entire graph.
+func SingleSourceDijkstra(g graph.Graph, source graph.Node, cutoff float64) (*ShortestPathTree, error) {
+	return singleSourceDijkstra(context.Background(), g, source, graph.Node{}, cutoff)
+}
+
+// singleSourceDijkstra searches outwards from source until target is reached, or until every node within cutoff has
+// been reached if target is zero.
+func singleSourceDijkstra(ctx context.Context, g graph.Graph, source, target graph.Node, cutoff float64) (*ShortestPathTree, error) {
+	if target.IsZero() {
+		return multiTargetDijkstra(ctx, g, source, nil, cutoff)
 	}
+	return multiTargetDijkstra(ctx, g, source, []graph.Node{target}, cutoff)
+}
 
-	costs := map[graph.Node]float64{}     // Dictionary of final costs
-	paths := map[graph.Node][]graph.Node{ // Dictionary of paths
-		source: {source},
+// multiTargetDijkstra searches outwards from source until every one of the targets (matched by ID) is reached, or
+// until every node within cutoff has been reached if there are no targets.
+func multiTargetDijkstra(ctx context.Context, g graph.Graph, source graph.Node, targets []graph.Node, cutoff float64) (*ShortestPathTree, error) {
+	remaining := make(map[int]bool, len(targets)) // Targets which have not yet been reached
+	for _, n := range targets {
+		remaining[n.ID()] = true
 	}
-	seen := map[graph.Node]float64{
-		source: 0.0,
+	t := &ShortestPathTree{
+		Source: source,
+		Costs: map[graph.Node]float64{ // Final costs
+			source: 0.0,
+		},
+		Predecessors: map[graph.Node]graph.Node{},
+		g:            g,
 	}
-	fringe := lane.NewPQueue(lane.MINPQ)
+	fringe := newNodeHeap()
 	fringe.Push(source, 0)
-
-	for fringe.Size() > 0 {
-		_v, d := fringe.Pop()
-		v := _v.(graph.Node)
-		if _, ok := costs[v]; ok { // Already searched this node
-			continue
+	for i := 0; fringe.Len() > 0; i++ {
+		if i%cancellationInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
 		}
-		costs[v] = float64(d) / priorityExponent
-		if v == target {
-			break
+
+		v, vDist := fringe.Pop()
+		t.Costs[v] = vDist
+		if len(targets) > 0 {
+			delete(remaining, v.ID())
+			if len(remaining) == 0 {
+				break
+			}
 		}
 
 		for _, w := range g.Successors(v) {
 			edge := g.EdgeTo(v, w)
-			vwDist := costs[v] + edge.Cost
+			vwDist := vDist + edge.Cost
 			if vwDist > cutoff {
 				continue
 			}
-			if wDist, ok := costs[w]; ok {
+			if wDist, ok := t.Costs[w]; ok {
 				if vwDist < wDist {
-					return nil, nil, ErrContradiction
+					return nil, ErrContradiction
 				}
-			} else if wSeen, ok := seen[w]; !ok || vwDist < wSeen {
-				seen[w] = vwDist
-				fringe.Push(w, int(vwDist*priorityExponent))
-				paths[w] = append(paths[v], w)
+			} else if wSeen, ok := fringe.Priority(w); !ok || vwDist < wSeen {
+				fringe.Push(w, vwDist)
+				t.Predecessors[w] = v
 			}
 		}
 	}
 
-	return paths, costs, nil
+	// Predecessors may have been recorded for nodes which were queued but never reached
+	for n := range t.Predecessors {
+		if _, ok := t.Costs[n]; !ok {
+			delete(t.Predecessors, n)
+		}
+	}
+	return t, nil
 }
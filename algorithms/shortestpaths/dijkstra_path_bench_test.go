@@ -0,0 +1,72 @@
+package shortestpaths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// gridGraph generates a size×size grid, with edges in both directions between adjacent nodes
+func gridGraph(size int) graph.Graph {
+	g := graph.NewGraph()
+	id := func(x, y int) graph.Node {
+		return graph.Node{Id: y*size + x + 1}
+	}
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			cost := float64((x*7+y*13)%10 + 1)
+			if x+1 < size {
+				g.AddDirectedEdge(&graph.Edge{H: id(x, y), T: id(x+1, y), Cost: cost})
+				g.AddDirectedEdge(&graph.Edge{H: id(x+1, y), T: id(x, y), Cost: cost})
+			}
+			if y+1 < size {
+				g.AddDirectedEdge(&graph.Edge{H: id(x, y), T: id(x, y+1), Cost: cost})
+				g.AddDirectedEdge(&graph.Edge{H: id(x, y+1), T: id(x, y), Cost: cost})
+			}
+		}
+	}
+
+	return g
+}
+
+func BenchmarkDijkstraPath(b *testing.B) {
+	g := gridGraph(100)
+	source, target := graph.Node{Id: 1}, graph.Node{Id: 100 * 100}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DijkstraPath(g, source, target)
+	}
+}
+
+func BenchmarkSingleSourceDijkstra(b *testing.B) {
+	g := gridGraph(100)
+	source := graph.Node{Id: 1}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		SingleSourceDijkstra(g, source, math.Inf(0))
+	}
+}
+
+func BenchmarkBidirectionalDijkstraPath(b *testing.B) {
+	g := gridGraph(100)
+	source, target := graph.Node{Id: 1}, graph.Node{Id: 100 * 100}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BidirectionalDijkstraPath(g, source, target)
+	}
+}
+
+func BenchmarkAStarPath(b *testing.B) {
+	g := gridGraph(100)
+	source, target := graph.Node{Id: 1}, graph.Node{Id: 100 * 100}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AStarPath(g, source, target, nil)
+	}
+}
@@ -1,9 +1,11 @@
 package shortestpaths
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -92,10 +94,16 @@ func (suite *DijkstraPathTestSuite) TestSingleSourceDijkstra() {
 		{6, 7},
 	}, 2)
 
-	paths, costs, err := singleSourceDijkstra(g, graph.Node{Id: 1}, graph.Node{}, math.Inf(0))
+	tree, err := singleSourceDijkstra(context.Background(), g, graph.Node{Id: 1}, graph.Node{}, math.Inf(0))
 	assert.NoError(t, err)
-	assert.Len(t, paths, 7) // Should include a path to itsel
-	assert.Len(t, costs, 7)
+	assert.Len(t, tree.Costs, 7) // Should include a path to itself
+	assert.Len(t, tree.Predecessors, 6)
+	paths := make(map[graph.Node][]graph.Node, len(tree.Costs))
+	for n := range tree.Costs {
+		path, err := tree.PathTo(n)
+		assert.NoError(t, err)
+		paths[n] = path.Nodes
+	}
 
 	validPaths := map[int][][]int{
 		1: {{1}},
@@ -122,8 +130,8 @@ destinationLoop:
 
 	assert.True(t, matched)
 
-	for destination, cost := range costs {
-		assert.Equal(t, (len(paths[destination])-1)*2, cost)
+	for destination, cost := range tree.Costs {
+		assert.Equal(t, float64((len(paths[destination])-1)*2), cost)
 	}
 }
 
@@ -167,11 +175,65 @@ func (suite *DijkstraPathTestSuite) TestDijkstraPath() {
 		assert.NoError(t, err, "Error retrieving path")
 	candidatePathLoop:
 		for _, candidatePath := range validPaths {
-			if suite.pathMatches(candidatePath, returnedPath) {
+			if suite.pathMatches(candidatePath, returnedPath.Nodes) {
 				matched = true
 				break candidatePathLoop
 			}
 		}
 		assert.True(t, matched, fmt.Sprintf("Valid path from %d -> %d not returned", origin, dest))
+		assert.Equal(t, float64(returnedPath.Len()*2), returnedPath.Cost)
+	}
+}
+
+func (suite *DijkstraPathTestSuite) TestSingleSourceDijkstraCutoff() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+		{3, 4},
+		{4, 5},
+		{4, 6},
+		{5, 7},
+		{6, 7},
+	}, 2)
+
+	reachable := map[float64][]int{
+		0:  {1},
+		1:  {1},
+		2:  {1, 2},
+		5:  {1, 2, 3},
+		6:  {1, 2, 3, 4},
+		9:  {1, 2, 3, 4, 5, 6},
+		10: {1, 2, 3, 4, 5, 6, 7},
+	}
+	for cutoff, expectedIds := range reachable {
+		tree, err := SingleSourceDijkstra(g, graph.Node{Id: 1}, cutoff)
+		assert.NoError(t, err)
+		assert.Len(t, tree.Costs, len(expectedIds), "Wrong nodes reached within %f", cutoff)
+		for _, id := range expectedIds {
+			assert.True(t, tree.Reachable(graph.Node{Id: id}))
+			assert.True(t, tree.Cost(graph.Node{Id: id}) <= cutoff)
+		}
 	}
 }
+
+func (suite *DijkstraPathTestSuite) TestDijkstraPathContext() {
+	t := suite.T()
+	g := gridGraph(50)
+	source, target := graph.Node{Id: 1}, graph.Node{Id: 50 * 50}
+
+	p, err := DijkstraPathContext(context.Background(), g, source, target)
+	assert.NoError(t, err)
+	assert.Equal(t, target, p.Target())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p, err = DijkstraPathContext(ctx, g, source, target)
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, p.IsZero())
+
+	ctx, cancel = context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	_, err = DijkstraPathContext(ctx, g, source, target)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
@@ -0,0 +1,107 @@
+package shortestpaths
+
+import (
+	"time"
+
+	"github.com/obeattie/vrp/graph"
+	"github.com/obeattie/vrp/route"
+)
+
+// A TimedPath is a path through a time-dependent graph, along with when each of its nodes is reached.
+type TimedPath struct {
+	// Path.Cost is the total travel time, in milliseconds.
+	Path
+	// Arrivals holds the time at which each node in the path is reached. The first is the departure time.
+	Arrivals []time.Time
+}
+
+// Departure returns the time the path leaves its source.
+func (p TimedPath) Departure() time.Time {
+	if len(p.Arrivals) == 0 {
+		return time.Time{}
+	}
+	return p.Arrivals[0]
+}
+
+// Arrival returns the time the path reaches its target.
+func (p TimedPath) Arrival() time.Time {
+	if len(p.Arrivals) == 0 {
+		return time.Time{}
+	}
+	return p.Arrivals[len(p.Arrivals)-1]
+}
+
+// Points returns a route point for each node in the path, visited at the time the path reaches it. The source and
+// target are waypoints.
+func (p TimedPath) Points() []route.Point {
+	result := make([]route.Point, len(p.Nodes))
+	for i, n := range p.Nodes {
+		result[i] = route.Point{
+			Arrival:    p.Arrivals[i],
+			Coordinate: route.Coordinate{n.Lng, n.Lat}, // Longitude first, as in route.Route.Graph
+			Departure:  p.Arrivals[i],
+			IsWaypoint: i == 0 || i == len(p.Nodes)-1,
+		}
+	}
+	return result
+}
+
+// EarliestArrival returns the path from source to target which, leaving at departAt, arrives soonest. The time taken
+// to traverse each edge depends on when it is entered, as given by the graph.
+//
+// This is a time-dependent Dijkstra search, which is correct provided every profile in the graph is FIFO: that is,
+// leaving later never means arriving earlier. If any travel time is negative, ErrContradiction is returned.
+func EarliestArrival(g graph.TimeDependentGraph, source, target graph.Node, departAt time.Time) (TimedPath, error) {
+	t := &ShortestPathTree{
+		Source: source,
+		Costs: map[graph.Node]float64{ // Milliseconds elapsed on arrival
+			source: 0.0,
+		},
+		Predecessors: map[graph.Node]graph.Node{},
+		g:            g,
+	}
+	arrivals := map[graph.Node]time.Time{
+		source: departAt,
+	}
+
+	fringe := newNodeHeap()
+	fringe.Push(source, 0)
+	for fringe.Len() > 0 {
+		v, vElapsed := fringe.Pop()
+		t.Costs[v] = vElapsed
+		if v == target {
+			break
+		}
+
+		for _, w := range g.Successors(v) {
+			if _, ok := t.Costs[w]; ok {
+				continue
+			}
+			travelTime := g.TravelTime(g.EdgeTo(v, w), arrivals[v])
+			if travelTime < 0 {
+				return TimedPath{}, ErrContradiction
+			}
+			wArrival := arrivals[v].Add(travelTime)
+			wElapsed := float64(wArrival.Sub(departAt)) / float64(time.Millisecond)
+			if wSeen, ok := fringe.Priority(w); !ok || wElapsed < wSeen {
+				fringe.Push(w, wElapsed)
+				t.Predecessors[w] = v
+				arrivals[w] = wArrival
+			}
+		}
+	}
+
+	path, err := t.PathTo(target)
+	if err != nil {
+		return TimedPath{}, err
+	}
+	path.Cost = t.Costs[target]
+	result := TimedPath{
+		Path:     path,
+		Arrivals: make([]time.Time, len(path.Nodes)),
+	}
+	for i, n := range path.Nodes {
+		result.Arrivals[i] = arrivals[n]
+	}
+	return result, nil
+}
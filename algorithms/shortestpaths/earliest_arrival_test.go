@@ -0,0 +1,144 @@
+package shortestpaths
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestEarliestArrival(t *testing.T) {
+	suite.Run(t, new(EarliestArrivalTestSuite))
+}
+
+type EarliestArrivalTestSuite struct {
+	suite.Suite
+	g graph.TimeDependentGraph
+}
+
+func (suite *EarliestArrivalTestSuite) at(hour, minute int) time.Time {
+	return time.Date(2016, 3, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func (suite *EarliestArrivalTestSuite) SetupTest() {
+	// The motorway (via 2) is quicker, except in the morning rush hour
+	g := graph.NewTimeDependentGraph(graph.NewGraph())
+	minutes := float64(time.Minute / time.Millisecond)
+	for _, e := range []costedEdgePrototype{
+		{1, 2, 10 * minutes},
+		{2, 4, 10 * minutes},
+		{1, 3, 20 * minutes},
+		{3, 4, 20 * minutes},
+	} {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: e.srcId},
+			T:    graph.Node{Id: e.targetId},
+			Cost: e.cost,
+		})
+	}
+	rushHour, err := graph.NewPiecewiseLinearProfile(
+		graph.Breakpoint{Offset: 7 * time.Hour, TravelTime: 10 * time.Minute},
+		graph.Breakpoint{Offset: 8 * time.Hour, TravelTime: time.Hour},
+		graph.Breakpoint{Offset: 9 * time.Hour, TravelTime: time.Hour},
+		graph.Breakpoint{Offset: 10 * time.Hour, TravelTime: 10 * time.Minute})
+	suite.Require().NoError(err)
+	g.SetProfile(graph.Node{Id: 1}, graph.Node{Id: 2}, rushHour)
+	g.SetProfile(graph.Node{Id: 2}, graph.Node{Id: 4}, rushHour)
+	suite.g = g
+}
+
+func (suite *EarliestArrivalTestSuite) ids(nodes []graph.Node) []int {
+	result := make([]int, len(nodes))
+	for i, n := range nodes {
+		result[i] = n.ID()
+	}
+	return result
+}
+
+func (suite *EarliestArrivalTestSuite) TestDepartureTimes() {
+	t := suite.T()
+
+	path, err := EarliestArrival(suite.g, graph.Node{Id: 1}, graph.Node{Id: 4}, suite.at(3, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 4}, suite.ids(path.Nodes))
+	assert.Equal(t, []time.Time{suite.at(3, 0), suite.at(3, 10), suite.at(3, 20)}, path.Arrivals)
+	assert.Equal(t, suite.at(3, 0), path.Departure())
+	assert.Equal(t, suite.at(3, 20), path.Arrival())
+	assert.Equal(t, 20*time.Minute, path.Duration())
+
+	path, err = EarliestArrival(suite.g, graph.Node{Id: 1}, graph.Node{Id: 4}, suite.at(8, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3, 4}, suite.ids(path.Nodes))
+	assert.Equal(t, suite.at(8, 40), path.Arrival())
+
+	// Leaving at 06:50, the first edge is quick, but the second is entered in the build-up to rush hour
+	path, err = EarliestArrival(suite.g, graph.Node{Id: 1}, graph.Node{Id: 4}, suite.at(6, 50))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 4}, suite.ids(path.Nodes))
+	assert.Equal(t, []time.Time{suite.at(6, 50), suite.at(7, 0), suite.at(7, 10)}, path.Arrivals)
+	path, err = EarliestArrival(suite.g, graph.Node{Id: 1}, graph.Node{Id: 4}, suite.at(7, 20))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3, 4}, suite.ids(path.Nodes))
+	assert.Equal(t, suite.at(8, 0), path.Arrival())
+}
+
+func (suite *EarliestArrivalTestSuite) TestPoints() {
+	t := suite.T()
+	g := graph.NewTimeDependentGraph(graph.NewGraph())
+	nodes := []graph.Node{
+		{Id: 1, Lat: 51.5, Lng: -0.1},
+		{Id: 2, Lat: 51.6, Lng: -0.2},
+		{Id: 3, Lat: 51.7, Lng: -0.3},
+	}
+	g.AddDirectedEdge(&graph.Edge{H: nodes[0], T: nodes[1], Cost: float64(10 * time.Minute / time.Millisecond)})
+	g.AddDirectedEdge(&graph.Edge{H: nodes[1], T: nodes[2], Cost: float64(10 * time.Minute / time.Millisecond)})
+	path, err := EarliestArrival(g, nodes[0], nodes[2], suite.at(3, 0))
+	assert.NoError(t, err)
+
+	points := path.Points()
+	assert.Len(t, points, 3)
+	assert.True(t, points[0].IsWaypoint)
+	assert.False(t, points[1].IsWaypoint)
+	assert.True(t, points[2].IsWaypoint)
+	assert.Equal(t, suite.at(3, 10), points[1].Arrival)
+	assert.Equal(t, time.Duration(0), points[1].Dwell())
+	assert.Equal(t, -0.2, points[1].Coordinate[0])
+	assert.Equal(t, 51.6, points[1].Coordinate[1])
+}
+
+func (suite *EarliestArrivalTestSuite) TestErrors() {
+	t := suite.T()
+	suite.g.AddNode(graph.Node{Id: 5})
+	_, err := EarliestArrival(suite.g, graph.Node{Id: 1}, graph.Node{Id: 5}, suite.at(3, 0))
+	assert.Equal(t, ErrUnreachable, err)
+
+	suite.g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 4}, T: graph.Node{Id: 5}, Cost: -1})
+	_, err = EarliestArrival(suite.g, graph.Node{Id: 1}, graph.Node{Id: 5}, suite.at(3, 0))
+	assert.Equal(t, ErrContradiction, err)
+}
+
+func (suite *EarliestArrivalTestSuite) TestMatchesDijkstraWithoutProfiles() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(14))
+	g := graph.NewTimeDependentGraph(randomGeometricGraph(rng, 200, 1200))
+	nodes := g.NodeList()
+
+	reachable := 0
+	for i := 0; i < 20; i++ {
+		s, u := nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))]
+		expected, expectedErr := DijkstraPath(g, s, u)
+		path, err := EarliestArrival(g, s, u, suite.at(12, 0))
+		assert.Equal(t, expectedErr, err)
+		if err == nil {
+			reachable++
+			// Travel times are truncated to whole nanoseconds
+			assert.True(t, math.Abs(expected.Cost-path.Cost) < 1e-3)
+		}
+	}
+	assert.NotZero(t, reachable)
+}
@@ -0,0 +1,96 @@
+package shortestpaths
+
+import (
+	"github.com/obeattie/vrp/graph"
+)
+
+// filteredGraph is a read-only view of a graph with some of its nodes and edges hidden. It allows searches to exclude
+// parts of a graph without copying or mutating it.
+type filteredGraph struct {
+	graph.ReadOnly
+	hiddenNodes map[int]bool    // By node ID
+	hiddenEdges map[[2]int]bool // By (head, tail) node IDs
+}
+
+func newFilteredGraph(g graph.Graph) *filteredGraph {
+	return &filteredGraph{
+		ReadOnly:    graph.ReadOnly{Graph: g},
+		hiddenNodes: map[int]bool{},
+		hiddenEdges: map[[2]int]bool{},
+	}
+}
+
+func (g *filteredGraph) hideNode(n graph.Node) {
+	g.hiddenNodes[n.ID()] = true
+}
+
+func (g *filteredGraph) hideEdge(head, tail graph.Node) {
+	g.hiddenEdges[[2]int{head.ID(), tail.ID()}] = true
+}
+
+func (g *filteredGraph) edgeVisible(head, tail graph.Node) bool {
+	return !g.hiddenNodes[head.ID()] && !g.hiddenNodes[tail.ID()] && !g.hiddenEdges[[2]int{head.ID(), tail.ID()}]
+}
+
+func (g *filteredGraph) filterNodes(nodes []graph.Node, visible func(graph.Node) bool) []graph.Node {
+	result := make([]graph.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if visible(n) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+func (g *filteredGraph) NodeExists(n graph.Node) bool {
+	return !g.hiddenNodes[n.ID()] && g.Graph.NodeExists(n)
+}
+
+func (g *filteredGraph) NodeList() []graph.Node {
+	return g.filterNodes(g.Graph.NodeList(), func(n graph.Node) bool {
+		return !g.hiddenNodes[n.ID()]
+	})
+}
+
+func (g *filteredGraph) Neighbors(n graph.Node) []graph.Node {
+	return g.filterNodes(g.Graph.Neighbors(n), func(neighbour graph.Node) bool {
+		return g.edgeVisible(n, neighbour) || g.edgeVisible(neighbour, n)
+	})
+}
+
+func (g *filteredGraph) EdgeBetween(n, neighbour graph.Node) *graph.Edge {
+	if e := g.EdgeTo(n, neighbour); e != nil {
+		return e
+	}
+	return g.EdgeTo(neighbour, n)
+}
+
+func (g *filteredGraph) Successors(n graph.Node) []graph.Node {
+	if g.hiddenNodes[n.ID()] {
+		return nil
+	}
+	return g.filterNodes(g.Graph.Successors(n), func(successor graph.Node) bool {
+		return g.edgeVisible(n, successor)
+	})
+}
+
+func (g *filteredGraph) EdgeTo(n, successor graph.Node) *graph.Edge {
+	if !g.edgeVisible(n, successor) {
+		return nil
+	}
+	return g.Graph.EdgeTo(n, successor)
+}
+
+func (g *filteredGraph) Predecessors(n graph.Node) []graph.Node {
+	if g.hiddenNodes[n.ID()] {
+		return nil
+	}
+	return g.filterNodes(g.Graph.Predecessors(n), func(predecessor graph.Node) bool {
+		return g.edgeVisible(predecessor, n)
+	})
+}
+
+// Copy returns a mutable copy of the visible portion of the graph.
+func (g *filteredGraph) Copy() graph.Graph {
+	return graph.CopyOf(g)
+}
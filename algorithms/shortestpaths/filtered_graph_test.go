@@ -0,0 +1,91 @@
+package shortestpaths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestFilteredGraph(t *testing.T) {
+	suite.Run(t, new(FilteredGraphTestSuite))
+}
+
+type FilteredGraphTestSuite struct {
+	suite.Suite
+	g    graph.Graph
+	view *filteredGraph
+}
+
+func (suite *FilteredGraphTestSuite) SetupTest() {
+	g := graph.NewGraph()
+	for _, n := range []nodePrototype{
+		{1, 2},
+		{2, 3},
+		{3, 1},
+		{1, 3},
+	} {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: n.srcId},
+			T:    graph.Node{Id: n.targetId},
+			Cost: 1,
+		})
+	}
+
+	suite.g = g
+	suite.view = newFilteredGraph(g)
+}
+
+func (suite *FilteredGraphTestSuite) TestHideNode() {
+	t, g, view := suite.T(), suite.g, suite.view
+
+	view.hideNode(graph.Node{Id: 2})
+	assert.False(t, view.NodeExists(graph.Node{Id: 2}))
+	assert.Len(t, view.NodeList(), 2)
+	assert.Len(t, view.Successors(graph.Node{Id: 1}), 1)
+	assert.Len(t, view.Predecessors(graph.Node{Id: 3}), 1)
+	assert.Nil(t, view.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 2}))
+	assert.Nil(t, view.Successors(graph.Node{Id: 2}))
+	assert.Empty(t, view.Neighbors(graph.Node{Id: 2}))
+
+	// The underlying graph is unaffected
+	assert.True(t, g.NodeExists(graph.Node{Id: 2}))
+	assert.Len(t, g.NodeList(), 3)
+}
+
+func (suite *FilteredGraphTestSuite) TestHideEdge() {
+	t, g, view := suite.T(), suite.g, suite.view
+
+	view.hideEdge(graph.Node{Id: 1}, graph.Node{Id: 3})
+	assert.Nil(t, view.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 3}))
+	assert.NotNil(t, view.EdgeTo(graph.Node{Id: 3}, graph.Node{Id: 1}))
+	assert.NotNil(t, view.EdgeBetween(graph.Node{Id: 1}, graph.Node{Id: 3}))
+	assert.Len(t, view.Successors(graph.Node{Id: 1}), 1)
+	assert.Len(t, view.Predecessors(graph.Node{Id: 3}), 1)
+	assert.Len(t, view.Neighbors(graph.Node{Id: 1}), 2)
+
+	assert.NotNil(t, g.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 3}))
+}
+
+func (suite *FilteredGraphTestSuite) TestCopy() {
+	t, view := suite.T(), suite.view
+
+	view.hideNode(graph.Node{Id: 2})
+	view.hideEdge(graph.Node{Id: 1}, graph.Node{Id: 3})
+	c := view.Copy()
+	assert.Len(t, c.NodeList(), 2)
+	assert.Nil(t, c.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 3}))
+	assert.NotNil(t, c.EdgeTo(graph.Node{Id: 3}, graph.Node{Id: 1}))
+}
+
+func (suite *FilteredGraphTestSuite) TestReadOnly() {
+	t, view := suite.T(), suite.view
+
+	assert.Panics(t, func() { view.NewNode() })
+	assert.Panics(t, func() { view.AddNode(graph.Node{Id: 4}) })
+	assert.Panics(t, func() { view.RemoveNode(graph.Node{Id: 1}) })
+	assert.Panics(t, func() { view.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 1}, T: graph.Node{Id: 1}}) })
+	assert.Panics(t, func() { view.RemoveDirectedEdge(&graph.Edge{H: graph.Node{Id: 1}, T: graph.Node{Id: 2}}) })
+}
@@ -0,0 +1,200 @@
+package hl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// The first bytes of every encoding, identifying the format (and its version)
+var magic = [4]byte{'H', 'L', 'v', '1'}
+
+var ErrInvalidEncoding = errors.New("Invalid hub label encoding")
+
+// The encoding is compact: integers are varints, and hubs are delta-encoded within each label (so most take a single
+// byte). Costs are stored in full, as float64s.
+//
+//	magic
+//	node count
+//	for each node: ID, latitude, longitude
+//	for each node: out-label length, then for each entry: hub delta, cost
+//	for each node: in-label length, then for each entry: hub delta, cost
+
+// WriteTo writes the labels to w, implementing io.WriterTo.
+func (l *Labels) WriteTo(w io.Writer) (int64, error) {
+	buffered := bufio.NewWriter(w)
+	bw := &countingWriter{w: buffered}
+	buf := make([]byte, binary.MaxVarintLen64)
+	putUvarint := func(x uint64) {
+		bw.Write(buf[:binary.PutUvarint(buf, x)])
+	}
+	putFloat := func(f float64) {
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(f))
+		bw.Write(buf[:8])
+	}
+
+	bw.Write(magic[:])
+	putUvarint(uint64(len(l.nodes)))
+	for _, n := range l.nodes {
+		bw.Write(buf[:binary.PutVarint(buf, int64(n.Id))])
+		putFloat(n.Lat)
+		putFloat(n.Lng)
+	}
+	for _, labels := range [][][]entry{l.out, l.in} {
+		for _, label := range labels {
+			putUvarint(uint64(len(label)))
+			last := 0
+			for _, e := range label {
+				putUvarint(uint64(e.hub - last))
+				putFloat(e.cost)
+				last = e.hub
+			}
+		}
+	}
+
+	if bw.err == nil {
+		bw.err = buffered.Flush()
+	}
+	return bw.n, bw.err
+}
+
+// ReadFrom replaces the labels with those read from r, as written by WriteTo. It implements io.ReaderFrom.
+func (l *Labels) ReadFrom(r io.Reader) (int64, error) {
+	br := &countingReader{r: bufio.NewReader(r)}
+	fail := func(err error) (int64, error) {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = ErrInvalidEncoding
+		}
+		return br.n, err
+	}
+	buf := make([]byte, 8)
+	readFloat := func() (float64, error) {
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return 0, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(buf)), nil
+	}
+
+	var header [4]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return fail(err)
+	} else if header != magic {
+		return fail(ErrInvalidEncoding)
+	}
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return fail(err)
+	} else if count > math.MaxInt32 {
+		return fail(ErrInvalidEncoding)
+	}
+
+	// Sizes read from the input are untrusted, so nothing is allocated up front: a corrupt count fails once the input
+	// runs out, rather than exhausting memory
+	nodes := []graph.Node{}
+	for i := uint64(0); i < count; i++ {
+		id, err := binary.ReadVarint(br)
+		if err != nil {
+			return fail(err)
+		}
+		n := graph.Node{Id: int(id)}
+		if n.Lat, err = readFloat(); err != nil {
+			return fail(err)
+		}
+		if n.Lng, err = readFloat(); err != nil {
+			return fail(err)
+		}
+		nodes = append(nodes, n)
+	}
+	labels := [2][][]entry{}
+	for i := range labels {
+		labels[i] = make([][]entry, len(nodes))
+		for v := range nodes {
+			length, err := binary.ReadUvarint(br)
+			if err != nil {
+				return fail(err)
+			} else if length > uint64(len(nodes)) { // A label holds each hub at most once
+				return fail(ErrInvalidEncoding)
+			}
+			label := []entry{}
+			last := 0
+			for j := uint64(0); j < length; j++ {
+				delta, err := binary.ReadUvarint(br)
+				if err != nil {
+					return fail(err)
+				} else if delta >= uint64(len(nodes)-last) {
+					return fail(ErrInvalidEncoding)
+				}
+				e := entry{hub: last + int(delta)}
+				if e.cost, err = readFloat(); err != nil {
+					return fail(err)
+				}
+				label = append(label, e)
+				last = e.hub
+			}
+			labels[i][v] = label
+		}
+	}
+
+	l.nodes = nodes
+	l.index = make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		l.index[n.ID()] = i
+	}
+	l.out, l.in = labels[0], labels[1]
+	return br.n, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, so labels may be precomputed and stored.
+func (l *Labels) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	_, err := l.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, restoring labels stored by MarshalBinary.
+func (l *Labels) UnmarshalBinary(data []byte) error {
+	_, err := l.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// countingWriter counts the bytes written through it, and remembers the first error
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	w.err = err
+	return n, err
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *countingReader) ReadByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == nil {
+		r.n++
+	}
+	return b, err
+}
@@ -0,0 +1,89 @@
+package hl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestEncodingRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(17))
+	g := randomGraph(rng, 200)
+	g.AddNode(graph.Node{Id: 1000, Lat: 51.5, Lng: -0.1})
+	l, err := New(g)
+	assert.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	n, err := l.WriteTo(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	// Each entry takes a byte or two for its hub, and eight for its cost
+	assert.True(t, buf.Len() < l.Size()*11+len(g.NodeList())*24)
+
+	decoded := new(Labels)
+	read, err := decoded.ReadFrom(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, n, read)
+	assert.Equal(t, l, decoded)
+
+	data, err := l.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), data)
+	decoded = new(Labels)
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, l, decoded)
+
+	nodes := g.NodeList()
+	for i := 0; i < 50; i++ {
+		source, target := nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))]
+		expected, expectedErr := l.Distance(source, target)
+		actual, err := decoded.Distance(source, target)
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	assert.Equal(t, ErrInvalidEncoding, new(Labels).UnmarshalBinary([]byte("not hub labels")))
+	assert.Equal(t, ErrInvalidEncoding, new(Labels).UnmarshalBinary(nil))
+
+	l, err := New(randomGraph(rand.New(rand.NewSource(17)), 20))
+	assert.NoError(t, err)
+	data, err := l.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, ErrInvalidEncoding, new(Labels).UnmarshalBinary(data[:len(data)-1]))
+}
+
+func TestUnmarshalHostileSizes(t *testing.T) {
+	// encode concatenates the magic bytes with the given (pre-encoded) fields
+	encode := func(fields ...[]byte) []byte {
+		return bytes.Join(append([][]byte{magic[:]}, fields...), nil)
+	}
+	uvarint := func(v uint64) []byte {
+		buf := make([]byte, binary.MaxVarintLen64)
+		return buf[:binary.PutUvarint(buf, v)]
+	}
+	node := []byte{2} // ID 1, as a zig-zag varint
+	node = append(node, make([]byte, 16)...)
+
+	// Implausible node counts fail without allocating for them
+	assert.Equal(t, ErrInvalidEncoding, new(Labels).UnmarshalBinary(encode(uvarint(math.MaxUint64))))
+	assert.Equal(t, ErrInvalidEncoding, new(Labels).UnmarshalBinary(encode(uvarint(math.MaxInt32))))
+	// A label longer than there are nodes
+	assert.Equal(t, ErrInvalidEncoding, new(Labels).UnmarshalBinary(encode(uvarint(1), node, uvarint(1<<40))))
+	// A hub beyond the last node
+	assert.Equal(t, ErrInvalidEncoding, new(Labels).UnmarshalBinary(encode(uvarint(1), node, uvarint(1), uvarint(5),
+		make([]byte, 8))))
+
+	// The same node, with well-formed labels, decodes
+	valid := encode(uvarint(1), node, uvarint(1), uvarint(0), make([]byte, 8), uvarint(1), uvarint(0), make([]byte, 8))
+	l := new(Labels)
+	assert.NoError(t, l.UnmarshalBinary(valid))
+	assert.Equal(t, 2, l.Size())
+}
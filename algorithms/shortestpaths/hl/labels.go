@@ -0,0 +1,301 @@
+// Package hl implements hub labeling: a preprocessing step which gives every node a label of "hubs" along with the
+// costs to and from them, chosen so that the shortest path between any two nodes passes through a hub common to both
+// their labels. Distance queries then need only intersect two short, sorted lists, without searching the graph at all.
+package hl
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/algorithms/shortestpaths/internal/pq"
+	"github.com/obeattie/vrp/graph"
+)
+
+// An entry in a label: a hub (identified by its rank), and the cost between it and the label's node
+type entry struct {
+	hub  int
+	cost float64
+}
+
+// An adjacency is an edge in the graph, by the index of the node at its other end
+type adjacency struct {
+	node int
+	cost float64
+}
+
+// Labels are a hub labeling of a graph, which can answer distance queries far faster than searching the graph. Labels
+// are immutable and may be queried concurrently.
+type Labels struct {
+	nodes []graph.Node
+	index map[int]int // Node ID -> index within nodes
+	// out[v] holds the hubs reachable from v, and in[v] the hubs from which v is reachable, both in increasing order
+	// of hub rank.
+	out, in [][]entry
+}
+
+// New builds hub labels for the given graph. Changes to the graph after the labels are built are not reflected in
+// their results.
+//
+// Labels are built by pruned landmark labeling [1]: a Dijkstra search is run (forwards and backwards) from every node
+// in turn, in decreasing order of importance, but pruned wherever the labels built so far already give the correct
+// cost. A node's importance is estimated by how many shortest paths pass through it, from the sizes of the subtrees
+// below it in a sample of shortest path trees.
+//
+// Negative-cost edges are not supported; if any are found, ErrContradiction is returned.
+//
+// [1] Akiba, T. et al. Fast Exact Shortest-Path Distance Queries on Large Networks by Pruned Landmark Labeling (2013).
+func New(g graph.Graph) (*Labels, error) {
+	nodes := g.NodeList()
+	sort.Sort(graph.NodesById(nodes)) // Determinism: importance is sampled by position, and ties are broken by order
+	index := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		index[n.ID()] = i
+	}
+	succ, pred := make([][]adjacency, len(nodes)), make([][]adjacency, len(nodes))
+	for i, u := range nodes {
+		successors := g.Successors(u)
+		sort.Sort(graph.NodesById(successors))
+		for _, v := range successors {
+			if v.ID() == u.ID() { // Loops can never be part of a shortest path
+				continue
+			}
+			edge := g.EdgeTo(u, v)
+			if edge.Cost < 0 {
+				return nil, shortestpaths.ErrContradiction
+			}
+			j := index[v.ID()]
+			succ[i] = append(succ[i], adjacency{node: j, cost: edge.Cost})
+			pred[j] = append(pred[j], adjacency{node: i, cost: edge.Cost})
+		}
+	}
+
+	// Hubs are processed in decreasing order of importance, so that the first hubs cover as many shortest paths as
+	// possible (and the labels stay small)
+	order := make([]int, len(nodes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Sort(byImportance{order: order, nodes: nodes, importance: importance(succ)})
+
+	l := &Labels{
+		nodes: nodes,
+		index: index,
+		out:   make([][]entry, len(nodes)),
+		in:    make([][]entry, len(nodes)),
+	}
+	s := newPrunedSearch(len(nodes))
+	for rank, hub := range order {
+		// Forwards from the hub, adding it to the in-labels of the nodes it reaches
+		s.run(hub, succ, l.out[hub], func(v int, cost float64) bool {
+			if s.covered(l.in[v], cost) {
+				return false
+			}
+			l.in[v] = append(l.in[v], entry{hub: rank, cost: cost})
+			return true
+		})
+		// And backwards, adding it to the out-labels of the nodes which reach it
+		s.run(hub, pred, l.in[hub], func(v int, cost float64) bool {
+			if s.covered(l.out[v], cost) {
+				return false
+			}
+			l.out[v] = append(l.out[v], entry{hub: rank, cost: cost})
+			return true
+		})
+	}
+	return l, nil
+}
+
+// Distance returns the cost of the shortest path from source to target.
+func (l *Labels) Distance(source, target graph.Node) (float64, error) {
+	s, sOk := l.index[source.ID()]
+	t, tOk := l.index[target.ID()]
+	if !sOk || !tOk {
+		return math.Inf(0), shortestpaths.ErrUnreachable
+	}
+	if s == t {
+		return 0, nil
+	}
+
+	cost := intersect(l.out[s], l.in[t])
+	if math.IsInf(cost, 1) {
+		return cost, shortestpaths.ErrUnreachable
+	}
+	return cost, nil
+}
+
+// Size returns the total number of entries in all labels, which governs the memory used and the speed of queries.
+func (l *Labels) Size() int {
+	size := 0
+	for i := range l.nodes {
+		size += len(l.out[i]) + len(l.in[i])
+	}
+	return size
+}
+
+// intersect returns the lowest cost through a hub common to an out-label and an in-label, by merging them
+func intersect(out, in []entry) float64 {
+	best := math.Inf(0)
+	for i, j := 0, 0; i < len(out) && j < len(in); {
+		switch {
+		case out[i].hub < in[j].hub:
+			i++
+		case out[i].hub > in[j].hub:
+			j++
+		default:
+			if cost := out[i].cost + in[j].cost; cost < best {
+				best = cost
+			}
+			i++
+			j++
+		}
+	}
+	return best
+}
+
+// prunedSearch holds the state of the pruned Dijkstra searches run during construction, reused between searches.
+type prunedSearch struct {
+	costs    []float64
+	visited  []int
+	hubCosts []float64 // The label of the current search's source, by hub rank
+	hubSet   []int
+}
+
+func newPrunedSearch(n int) *prunedSearch {
+	s := &prunedSearch{
+		costs:    make([]float64, n),
+		hubCosts: make([]float64, n),
+	}
+	for i := range s.costs {
+		s.costs[i] = math.Inf(0)
+		s.hubCosts[i] = math.Inf(0)
+	}
+	return s
+}
+
+// run searches outwards from source along the given adjacencies. Each node settled is passed to visit, which returns
+// whether the search should continue past it. The source's label (out- or in-label, matching the direction of the
+// search) is loaded for the duration of the search, for use by covered.
+func (s *prunedSearch) run(source int, adjacencies [][]adjacency, label []entry, visit func(v int, cost float64) bool) {
+	for _, e := range label {
+		s.hubCosts[e.hub] = e.cost
+		s.hubSet = append(s.hubSet, e.hub)
+	}
+
+	fringe := &pq.Queue{{Node: source}}
+	s.costs[source] = 0
+	s.visited = append(s.visited, source)
+	for fringe.Len() > 0 {
+		item := heap.Pop(fringe).(pq.Item)
+		v, vCost := item.Node, item.Priority
+		if vCost > s.costs[v] { // Stale
+			continue
+		}
+		if !visit(v, vCost) {
+			continue
+		}
+		for _, a := range adjacencies[v] {
+			if wCost := vCost + a.cost; wCost < s.costs[a.node] {
+				if math.IsInf(s.costs[a.node], 1) {
+					s.visited = append(s.visited, a.node)
+				}
+				s.costs[a.node] = wCost
+				heap.Push(fringe, pq.Item{Node: a.node, Priority: wCost})
+			}
+		}
+	}
+
+	for _, v := range s.visited {
+		s.costs[v] = math.Inf(0)
+	}
+	s.visited = s.visited[:0]
+	for _, hub := range s.hubSet {
+		s.hubCosts[hub] = math.Inf(0)
+	}
+	s.hubSet = s.hubSet[:0]
+}
+
+// covered returns whether the labels built so far already give a cost of no more than cost between the source of the
+// current search and a node with the given (opposite) label.
+func (s *prunedSearch) covered(label []entry, cost float64) bool {
+	for _, e := range label {
+		if s.hubCosts[e.hub]+e.cost <= cost {
+			return true
+		}
+	}
+	return false
+}
+
+// The number of shortest path trees sampled to estimate the importance of nodes
+const importanceSamples = 16
+
+// importance estimates how many shortest paths pass through each node, by summing the sizes of the subtrees below it
+// in shortest path trees from a sample of sources.
+func importance(succ [][]adjacency) []float64 {
+	n := len(succ)
+	result := make([]float64, n)
+	costs, parents := make([]float64, n), make([]int, n)
+	for sample := 0; sample < importanceSamples && sample < n; sample++ {
+		source := sample * n / importanceSamples
+		if importanceSamples > n {
+			source = sample
+		}
+		for i := range costs {
+			costs[i], parents[i] = math.Inf(0), -1
+		}
+
+		settled := make([]int, 0, n)
+		costs[source] = 0
+		fringe := &pq.Queue{{Node: source}}
+		for fringe.Len() > 0 {
+			item := heap.Pop(fringe).(pq.Item)
+			v := item.Node
+			if item.Priority > costs[v] { // Stale
+				continue
+			}
+			settled = append(settled, v)
+			for _, a := range succ[v] {
+				if wCost := item.Priority + a.cost; wCost < costs[a.node] {
+					costs[a.node], parents[a.node] = wCost, v
+					heap.Push(fringe, pq.Item{Node: a.node, Priority: wCost})
+				}
+			}
+		}
+
+		// Nodes are settled after their parents, so subtree sizes accumulate in reverse order
+		sizes := make([]float64, n)
+		for i := len(settled) - 1; i >= 0; i-- {
+			v := settled[i]
+			sizes[v]++
+			result[v] += sizes[v]
+			if p := parents[v]; p >= 0 {
+				sizes[p] += sizes[v]
+			}
+		}
+	}
+	return result
+}
+
+// byImportance sorts node indices in decreasing order of importance, then by ID
+type byImportance struct {
+	order      []int
+	nodes      []graph.Node
+	importance []float64
+}
+
+func (b byImportance) Len() int {
+	return len(b.order)
+}
+
+func (b byImportance) Less(i, j int) bool {
+	u, v := b.order[i], b.order[j]
+	if b.importance[u] != b.importance[v] {
+		return b.importance[u] > b.importance[v]
+	}
+	return b.nodes[u].ID() < b.nodes[v].ID()
+}
+
+func (b byImportance) Swap(i, j int) {
+	b.order[i], b.order[j] = b.order[j], b.order[i]
+}
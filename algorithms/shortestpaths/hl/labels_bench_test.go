@@ -0,0 +1,77 @@
+package hl
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/graph"
+)
+
+// gridGraph generates a size x size grid of nodes, with edges in both directions between neighbours
+func gridGraph(size int) graph.Graph {
+	g := graph.NewGraph()
+	id := func(x, y int) graph.Node {
+		return graph.Node{Id: y*size + x + 1}
+	}
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			cost := float64((x*7+y*13)%10 + 1)
+			if x+1 < size {
+				g.AddDirectedEdge(&graph.Edge{H: id(x, y), T: id(x+1, y), Cost: cost})
+				g.AddDirectedEdge(&graph.Edge{H: id(x+1, y), T: id(x, y), Cost: cost})
+			}
+			if y+1 < size {
+				g.AddDirectedEdge(&graph.Edge{H: id(x, y), T: id(x, y+1), Cost: cost})
+				g.AddDirectedEdge(&graph.Edge{H: id(x, y+1), T: id(x, y), Cost: cost})
+			}
+		}
+	}
+
+	return g
+}
+
+// benchmarkQueries are random pairs of nodes in a 50x50 grid
+func benchmarkQueries() [][2]graph.Node {
+	rng := rand.New(rand.NewSource(17))
+	queries := make([][2]graph.Node, 100)
+	for i := range queries {
+		queries[i] = [2]graph.Node{{Id: 1 + rng.Intn(2500)}, {Id: 1 + rng.Intn(2500)}}
+	}
+	return queries
+}
+
+func BenchmarkDistance(b *testing.B) {
+	l, err := New(gridGraph(50))
+	if err != nil {
+		b.Fatal(err)
+	}
+	queries := benchmarkQueries()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q := queries[i%len(queries)]
+		l.Distance(q[0], q[1])
+	}
+}
+
+func BenchmarkDijkstraPath(b *testing.B) {
+	g := gridGraph(50)
+	queries := benchmarkQueries()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q := queries[i%len(queries)]
+		shortestpaths.DijkstraPath(g, q[0], q[1])
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	g := gridGraph(50)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		New(g)
+	}
+}
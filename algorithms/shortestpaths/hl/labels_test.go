@@ -0,0 +1,148 @@
+package hl
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/algorithms/shortestpaths"
+	"github.com/obeattie/vrp/graph"
+)
+
+type nodePrototype struct {
+	srcId, targetId int
+}
+
+// randomGraph generates a sparse, randomly-costed graph of n nodes, each with a handful of edges to nearby nodes
+func randomGraph(rng *rand.Rand, n int) graph.Graph {
+	g := graph.NewGraph()
+	for i := 1; i <= n; i++ {
+		g.AddNode(graph.Node{Id: i})
+	}
+	for i := 1; i <= n; i++ {
+		for j := 0; j < 3; j++ {
+			target := i + rng.Intn(21) - 10
+			if target < 1 || target > n || target == i {
+				continue
+			}
+			g.AddDirectedEdge(&graph.Edge{
+				H:    graph.Node{Id: i},
+				T:    graph.Node{Id: target},
+				Cost: float64(1 + rng.Intn(100)),
+			})
+		}
+	}
+	return g
+}
+
+func TestLabels(t *testing.T) {
+	suite.Run(t, new(LabelsTestSuite))
+}
+
+type LabelsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *LabelsTestSuite) generateGraph(nodes []nodePrototype, costs float64) graph.Graph {
+	g := graph.NewGraph()
+
+	for _, n := range nodes {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: n.srcId},
+			T:    graph.Node{Id: n.targetId},
+			Cost: costs,
+		})
+	}
+
+	return g
+}
+
+func (suite *LabelsTestSuite) TestSimple() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+		{3, 4},
+		{1, 5},
+		{5, 4},
+		{4, 4},
+	}, 2.0)
+	g.AddNode(graph.Node{Id: 6})
+
+	l, err := New(g)
+	assert.NoError(t, err)
+	for _, c := range []struct {
+		source, target int
+		cost           float64
+	}{
+		{1, 4, 4},
+		{1, 3, 4},
+		{2, 4, 4},
+		{5, 4, 2},
+		{4, 4, 0},
+	} {
+		cost, err := l.Distance(graph.Node{Id: c.source}, graph.Node{Id: c.target})
+		assert.NoError(t, err)
+		assert.Equal(t, c.cost, cost, "%d -> %d", c.source, c.target)
+	}
+
+	for _, c := range []nodePrototype{{4, 1}, {1, 6}, {6, 1}, {1, 7}} {
+		cost, err := l.Distance(graph.Node{Id: c.srcId}, graph.Node{Id: c.targetId})
+		assert.Equal(t, shortestpaths.ErrUnreachable, err)
+		assert.True(t, math.IsInf(cost, 1))
+	}
+}
+
+func (suite *LabelsTestSuite) TestNegativeCost() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{{1, 2}}, -1.0)
+
+	_, err := New(g)
+	assert.Equal(t, shortestpaths.ErrContradiction, err)
+}
+
+func (suite *LabelsTestSuite) TestDeterministic() {
+	t := suite.T()
+	g := randomGraph(rand.New(rand.NewSource(17)), 300)
+	l, err := New(g)
+	assert.NoError(t, err)
+	expected, err := l.MarshalBinary()
+	assert.NoError(t, err)
+
+	// Node lists come back in arbitrary order, but the labels built from them must not depend on it
+	for i := 0; i < 5; i++ {
+		l, err := New(g)
+		assert.NoError(t, err)
+		actual, err := l.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func (suite *LabelsTestSuite) TestMatchesDijkstra() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(17))
+	g := randomGraph(rng, 500)
+	l, err := New(g)
+	assert.NoError(t, err)
+	assert.True(t, l.Size() > 0)
+
+	nodes := g.NodeList()
+	for i := 0; i < 50; i++ {
+		source := nodes[rng.Intn(len(nodes))]
+		tree, err := shortestpaths.SingleSourceDijkstra(g, source, math.Inf(0))
+		assert.NoError(t, err)
+		for _, target := range nodes {
+			cost, err := l.Distance(source, target)
+			if tree.Reachable(target) {
+				assert.NoError(t, err)
+				assert.Equal(t, tree.Cost(target), cost, "%d -> %d", source.ID(), target.ID())
+			} else {
+				assert.Equal(t, shortestpaths.ErrUnreachable, err)
+			}
+		}
+	}
+}
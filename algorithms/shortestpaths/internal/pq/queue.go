@@ -0,0 +1,34 @@
+// Package pq provides the priority queue of node indices shared by the preprocessing-based shortest path packages.
+package pq
+
+// An Item is a node, identified by its index, queued with a priority.
+type Item struct {
+	Node     int
+	Priority float64
+}
+
+// Queue is a min-heap of items, for use with container/heap.
+type Queue []Item
+
+func (q Queue) Len() int {
+	return len(q)
+}
+
+func (q Queue) Less(i, j int) bool {
+	return q[i].Priority < q[j].Priority
+}
+
+func (q Queue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *Queue) Push(x interface{}) {
+	*q = append(*q, x.(Item))
+}
+
+func (q *Queue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
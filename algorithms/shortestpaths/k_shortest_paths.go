@@ -0,0 +1,109 @@
+package shortestpaths
+
+import (
+	"sort"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// KShortestPaths returns up to k loopless paths from source to target, in increasing order of cost. The first path is
+// always the shortest path (as returned by DijkstraPath); fewer than k paths are returned if no more exist.
+//
+// This algorithm is Yen's [1]. The graph is not modified: edges and nodes are excluded from each search using a
+// filtered view.
+//
+// [1] Yen, J. Y. Finding the K Shortest Loopless Paths in a Network (Management Science 17(11), 1971).
+func KShortestPaths(g graph.Graph, source, target graph.Node, k int) ([]Path, error) {
+	if k < 1 {
+		return nil, nil
+	}
+	shortest, err := DijkstraPath(g, source, target)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []Path{shortest}
+	candidates := make([]Path, 0, k)
+	for len(paths) < k {
+		last := paths[len(paths)-1]
+
+		// Each node of the previous path (except the target) is used as a "spur" from which a deviation is sought
+		for i := 0; i < len(last.Nodes)-1; i++ {
+			spur, root := last.Nodes[i], last.Nodes[:i+1]
+
+			view := newFilteredGraph(g)
+			for _, p := range paths { // Prevent paths sharing the root from being found again
+				if len(p.Nodes) > i+1 && nodesEqual(p.Nodes[:i+1], root) {
+					view.hideEdge(p.Nodes[i], p.Nodes[i+1])
+				}
+			}
+			for _, n := range root[:i] { // And prevent loops back through the root
+				view.hideNode(n)
+			}
+
+			spurPath, err := DijkstraPath(view, spur, target)
+			if err == ErrUnreachable {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			nodes := make([]graph.Node, 0, i+len(spurPath.Nodes))
+			nodes = append(nodes, root[:i]...)
+			nodes = append(nodes, spurPath.Nodes...)
+			candidate, err := NewPath(g, nodes)
+			if err != nil {
+				return nil, err
+			}
+			if !containsPath(paths, candidate) && !containsPath(candidates, candidate) {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+		sort.Stable(pathsByCost(candidates))
+		paths = append(paths, candidates[0])
+		candidates = candidates[1:]
+	}
+
+	return paths, nil
+}
+
+// nodesEqual returns whether two sequences of nodes have the same IDs.
+func nodesEqual(a, b []graph.Node) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, n := range a {
+		if n.ID() != b[i].ID() {
+			return false
+		}
+	}
+	return true
+}
+
+func containsPath(paths []Path, p Path) bool {
+	for _, candidate := range paths {
+		if nodesEqual(candidate.Nodes, p.Nodes) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathsByCost sorts paths in increasing order of cost
+type pathsByCost []Path
+
+func (p pathsByCost) Len() int {
+	return len(p)
+}
+
+func (p pathsByCost) Less(i, j int) bool {
+	return p[i].Cost < p[j].Cost
+}
+
+func (p pathsByCost) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
@@ -0,0 +1,132 @@
+package shortestpaths
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestKShortestPaths(t *testing.T) {
+	suite.Run(t, new(KShortestPathsTestSuite))
+}
+
+type KShortestPathsTestSuite struct {
+	suite.Suite
+	g graph.Graph
+}
+
+func (suite *KShortestPathsTestSuite) SetupTest() {
+	g := graph.NewGraph()
+	edges := [...]struct {
+		srcId, targetId int
+		cost            float64
+	}{
+		{1, 2, 3},
+		{1, 3, 2},
+		{2, 4, 4},
+		{3, 2, 1},
+		{3, 4, 2},
+		{3, 5, 3},
+		{4, 5, 2},
+		{4, 6, 1},
+		{5, 6, 2},
+	}
+
+	for _, e := range edges {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: e.srcId},
+			T:    graph.Node{Id: e.targetId},
+			Cost: e.cost,
+		})
+	}
+
+	suite.g = g
+}
+
+func (suite *KShortestPathsTestSuite) ids(p Path) []int {
+	result := make([]int, len(p.Nodes))
+	for i, n := range p.Nodes {
+		result[i] = n.ID()
+	}
+	return result
+}
+
+func (suite *KShortestPathsTestSuite) TestKShortestPaths() {
+	t, g := suite.T(), suite.g
+
+	paths, err := KShortestPaths(g, graph.Node{Id: 1}, graph.Node{Id: 6}, 3)
+	assert.NoError(t, err)
+	assert.Len(t, paths, 3)
+	assert.Equal(t, []int{1, 3, 4, 6}, suite.ids(paths[0]))
+	assert.Equal(t, 5.0, paths[0].Cost)
+	assert.Equal(t, []int{1, 3, 5, 6}, suite.ids(paths[1]))
+	assert.Equal(t, 7.0, paths[1].Cost)
+	assert.Contains(t, [][]int{{1, 2, 4, 6}, {1, 3, 2, 4, 6}}, suite.ids(paths[2]))
+	assert.Equal(t, 8.0, paths[2].Cost)
+
+	// The graph must be left untouched
+	assert.Len(t, g.NodeList(), 6)
+	assert.NotNil(t, g.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 3}))
+	assert.NotNil(t, g.EdgeTo(graph.Node{Id: 3}, graph.Node{Id: 4}))
+}
+
+func (suite *KShortestPathsTestSuite) TestExhausted() {
+	t, g := suite.T(), suite.g
+
+	paths, err := KShortestPaths(g, graph.Node{Id: 1}, graph.Node{Id: 6}, 100)
+	assert.NoError(t, err)
+	assert.Len(t, paths, 7) // Every loopless path from 1 to 6
+	for i := 1; i < len(paths); i++ {
+		assert.True(t, paths[i-1].Cost <= paths[i].Cost)
+	}
+}
+
+func (suite *KShortestPathsTestSuite) TestUnreachable() {
+	t, g := suite.T(), suite.g
+
+	paths, err := KShortestPaths(g, graph.Node{Id: 6}, graph.Node{Id: 1}, 3)
+	assert.Equal(t, ErrUnreachable, err)
+	assert.Nil(t, paths)
+
+	paths, err = KShortestPaths(g, graph.Node{Id: 1}, graph.Node{Id: 6}, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, paths)
+}
+
+func (suite *KShortestPathsTestSuite) TestRandom() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(7))
+	g := randomGeometricGraph(rng, 60, 1500)
+	nodes := g.NodeList()
+
+	for i := 0; i < 10; i++ {
+		source, target := nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))]
+		shortest, err := DijkstraPath(g, source, target)
+		paths, kErr := KShortestPaths(g, source, target, 5)
+		assert.Equal(t, err, kErr)
+		if err != nil {
+			continue
+		}
+
+		assert.InEpsilon(t, shortest.Cost+1, paths[0].Cost+1, 1e-6)
+		seen := map[string]bool{}
+		for j, p := range paths {
+			if j > 0 {
+				assert.True(t, paths[j-1].Cost <= p.Cost)
+			}
+			visited := map[int]bool{}
+			for _, n := range p.Nodes {
+				assert.False(t, visited[n.ID()], "Path contains a loop")
+				visited[n.ID()] = true
+			}
+			key := fmt.Sprint(suite.ids(p))
+			assert.False(t, seen[key], "Duplicate path")
+			seen[key] = true
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package shortestpaths
+
+import (
+	"math"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// Landmarks hold the costs of the shortest paths between every node in a graph and a small set of "landmark" nodes.
+// Through the triangle inequality, these give lower bounds on the cost of travelling between any two nodes, which are
+// usually far tighter than geometric bounds on road networks.
+//
+// Landmarks are only valid for the graph they were built from, and only for as long as it is unchanged.
+type Landmarks struct {
+	// Nodes are the landmarks.
+	Nodes []graph.Node
+	from  map[int][]float64 // Cost from each landmark to a node, by node ID
+	to    map[int][]float64 // Cost from a node to each landmark, by node ID
+}
+
+// NewLandmarks selects k landmarks from the graph and computes the costs between them and every other node.
+//
+// Landmarks are chosen by farthest-point selection: each is the node farthest from those already chosen, so that they
+// spread out around the periphery of the graph.
+func NewLandmarks(g graph.Graph, k int) (*Landmarks, error) {
+	nodes := g.NodeList()
+	l := &Landmarks{
+		Nodes: make([]graph.Node, 0, k),
+		from:  make(map[int][]float64, len(nodes)),
+		to:    make(map[int][]float64, len(nodes)),
+	}
+	if len(nodes) == 0 {
+		return l, nil
+	}
+	for _, n := range nodes {
+		l.from[n.ID()] = make([]float64, 0, k)
+		l.to[n.ID()] = make([]float64, 0, k)
+	}
+
+	// The distance from each node to its nearest landmark
+	nearest := make(map[int]float64, len(nodes))
+	for _, n := range nodes {
+		nearest[n.ID()] = math.Inf(0)
+	}
+	// Seed the selection with the node farthest from an arbitrary one (the node with the lowest ID, for determinism)
+	seed := nodes[0]
+	for _, n := range nodes {
+		if n.ID() < seed.ID() {
+			seed = n
+		}
+	}
+	seedTree, err := SingleSourceDijkstra(g, seed, math.Inf(0))
+	if err != nil {
+		return nil, err
+	}
+	for n, cost := range seedTree.Costs {
+		nearest[n.ID()] = cost
+	}
+
+	for len(l.Nodes) < k && len(l.Nodes) < len(nodes) {
+		landmark := nodes[0]
+		for _, n := range nodes {
+			if nearest[n.ID()] > nearest[landmark.ID()] ||
+				(nearest[n.ID()] == nearest[landmark.ID()] && n.ID() < landmark.ID()) {
+				landmark = n
+			}
+		}
+
+		fromTree, err := SingleSourceDijkstra(g, landmark, math.Inf(0))
+		if err != nil {
+			return nil, err
+		}
+		toTree, err := SingleSourceDijkstra(reversedGraph{graph.ReadOnly{Graph: g}}, landmark, math.Inf(0))
+		if err != nil {
+			return nil, err
+		}
+
+		l.Nodes = append(l.Nodes, landmark)
+		nearest[landmark.ID()] = -1 // Never choose the same landmark twice
+		for _, n := range nodes {
+			from, to := fromTree.Cost(n), toTree.Cost(n)
+			l.from[n.ID()] = append(l.from[n.ID()], from)
+			l.to[n.ID()] = append(l.to[n.ID()], to)
+			if from < nearest[n.ID()] {
+				nearest[n.ID()] = from
+			}
+		}
+	}
+
+	return l, nil
+}
+
+// LowerBound returns a lower bound on the cost of the shortest path from a to b. If either node was not in the graph
+// when the landmarks were built, the bound is zero.
+func (l *Landmarks) LowerBound(a, b graph.Node) float64 {
+	aFrom, bFrom := l.from[a.ID()], l.from[b.ID()]
+	aTo, bTo := l.to[a.ID()], l.to[b.ID()]
+	if aFrom == nil || bFrom == nil {
+		return 0
+	}
+
+	bound := 0.0
+	for i := range l.Nodes {
+		// d(L, b) <= d(L, a) + d(a, b)
+		if !math.IsInf(aFrom[i], 1) {
+			if lower := bFrom[i] - aFrom[i]; lower > bound {
+				bound = lower
+			}
+		}
+		// d(a, L) <= d(a, b) + d(b, L)
+		if !math.IsInf(bTo[i], 1) {
+			if lower := aTo[i] - bTo[i]; lower > bound {
+				bound = lower
+			}
+		}
+	}
+	return bound
+}
+
+// Heuristic returns a Heuristic for use with AStarPath, based on the landmarks' lower bounds.
+func (l *Landmarks) Heuristic() Heuristic {
+	return l.LowerBound
+}
+
+// ALTPath returns the shortest path from source to target using A* search directed by the lower bounds of the given
+// landmarks (hence A*, Landmarks, Triangle inequality). The landmarks must have been built from g.
+func ALTPath(g graph.Graph, source, target graph.Node, landmarks *Landmarks) (Path, error) {
+	return AStarPath(g, source, target, landmarks.Heuristic())
+}
@@ -0,0 +1,91 @@
+package shortestpaths
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestLandmarks(t *testing.T) {
+	suite.Run(t, new(LandmarksTestSuite))
+}
+
+type LandmarksTestSuite struct {
+	suite.Suite
+	g         graph.Graph
+	landmarks *Landmarks
+}
+
+func (suite *LandmarksTestSuite) SetupTest() {
+	rng := rand.New(rand.NewSource(11))
+	suite.g = randomGeometricGraph(rng, 150, 1200)
+	landmarks, err := NewLandmarks(suite.g, 4)
+	assert.NoError(suite.T(), err)
+	suite.landmarks = landmarks
+}
+
+func (suite *LandmarksTestSuite) TestSelection() {
+	t, landmarks := suite.T(), suite.landmarks
+
+	assert.Len(t, landmarks.Nodes, 4)
+	ids := map[int]bool{}
+	for _, n := range landmarks.Nodes {
+		assert.False(t, ids[n.ID()], "Landmark chosen twice")
+		ids[n.ID()] = true
+	}
+}
+
+func (suite *LandmarksTestSuite) TestLowerBound() {
+	t, g, landmarks := suite.T(), suite.g, suite.landmarks
+	nodes := g.NodeList()
+
+	nonTrivial := 0
+	for _, source := range nodes[:30] {
+		tree, err := SingleSourceDijkstra(g, source, math.Inf(0))
+		assert.NoError(t, err)
+		for _, target := range nodes {
+			bound := landmarks.LowerBound(source, target)
+			assert.True(t, bound <= tree.Cost(target)+1e-9, "Lower bound %f exceeds cost %f", bound, tree.Cost(target))
+			if bound > 0 {
+				nonTrivial++
+			}
+		}
+		assert.Equal(t, 0.0, landmarks.LowerBound(source, source))
+	}
+	assert.True(t, nonTrivial > 0)
+
+	assert.Equal(t, 0.0, landmarks.LowerBound(graph.Node{Id: -1}, nodes[0]))
+}
+
+func (suite *LandmarksTestSuite) TestALTPath() {
+	t, g, landmarks := suite.T(), suite.g, suite.landmarks
+	rng := rand.New(rand.NewSource(12))
+	nodes := g.NodeList()
+
+	for i := 0; i < 50; i++ {
+		source, target := nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))]
+		expected, expectedErr := DijkstraPath(g, source, target)
+		actual, err := ALTPath(g, source, target, landmarks)
+
+		assert.Equal(t, expectedErr, err)
+		if expectedErr != nil {
+			continue
+		}
+		assert.Equal(t, source, actual.Source())
+		assert.Equal(t, target, actual.Target())
+		assert.InEpsilon(t, expected.Cost+1, actual.Cost+1, 1e-9)
+	}
+}
+
+func (suite *LandmarksTestSuite) TestEmptyGraph() {
+	t := suite.T()
+
+	landmarks, err := NewLandmarks(graph.NewGraph(), 4)
+	assert.NoError(t, err)
+	assert.Empty(t, landmarks.Nodes)
+}
@@ -0,0 +1,126 @@
+package shortestpaths
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// A CostMatrix holds the costs of the shortest paths from each of a set of sources to each of a set of targets.
+// Lookups are keyed by node ID.
+type CostMatrix struct {
+	Sources []graph.Node
+	Targets []graph.Node
+	// Costs[i][j] is the cost of the shortest path from Sources[i] to Targets[j], or infinity if there is no such path.
+	Costs      [][]float64
+	sourceIdxs map[int]int
+	targetIdxs map[int]int
+}
+
+func newCostMatrix(sources, targets []graph.Node) *CostMatrix {
+	m := &CostMatrix{
+		Sources:    sources,
+		Targets:    targets,
+		Costs:      make([][]float64, len(sources)),
+		sourceIdxs: make(map[int]int, len(sources)),
+		targetIdxs: make(map[int]int, len(targets)),
+	}
+	for i, n := range sources {
+		m.sourceIdxs[n.ID()] = i
+		m.Costs[i] = make([]float64, len(targets))
+		for j := range m.Costs[i] {
+			m.Costs[i][j] = math.Inf(0)
+		}
+	}
+	for j, n := range targets {
+		m.targetIdxs[n.ID()] = j
+	}
+	return m
+}
+
+// Cost returns the cost of the shortest path from source to target, or infinity if there is no such path (or if either
+// node is not in the matrix).
+func (m *CostMatrix) Cost(source, target graph.Node) float64 {
+	i, iOk := m.sourceIdxs[source.ID()]
+	j, jOk := m.targetIdxs[target.ID()]
+	if !iOk || !jOk {
+		return math.Inf(0)
+	}
+	return m.Costs[i][j]
+}
+
+// Reachable returns whether there is a path from source to target.
+func (m *CostMatrix) Reachable(source, target graph.Node) bool {
+	return !math.IsInf(m.Cost(source, target), 1)
+}
+
+// Matrix returns the costs of the shortest paths from every source to every target. Searches from each source run in
+// parallel, on as many goroutines as there are usable CPUs, and each stops as soon as it has reached every target.
+func Matrix(g graph.Graph, sources, targets []graph.Node) (*CostMatrix, error) {
+	return MatrixWorkers(g, sources, targets, runtime.GOMAXPROCS(0))
+}
+
+// MatrixWorkers is like Matrix, but searches on no more than the given number of goroutines.
+func MatrixWorkers(g graph.Graph, sources, targets []graph.Node, workers int) (*CostMatrix, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	m := newCostMatrix(sources, targets)
+	if len(targets) == 0 { // Nothing to search for
+		return m, nil
+	}
+
+	work := make(chan int)
+	errs := make(chan error, workers)
+	wg := new(sync.WaitGroup)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				// The search stops once every target is reached, so needn't explore the whole graph
+				tree, err := multiTargetDijkstra(context.Background(), g, sources[i], targets, math.Inf(0))
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				costs := make(map[int]float64, len(tree.Costs))
+				for n, cost := range tree.Costs {
+					costs[n.ID()] = cost
+				}
+				for j, target := range targets {
+					if cost, ok := costs[target.ID()]; ok {
+						m.Costs[i][j] = cost
+					}
+				}
+			}
+		}()
+	}
+
+	// Hand out the sources, stopping early if any worker fails
+	var err error
+sourceLoop:
+	for i := range sources {
+		select {
+		case work <- i:
+		case err = <-errs:
+			break sourceLoop
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case err = <-errs:
+		return nil, err
+	default:
+		return m, nil
+	}
+}
@@ -0,0 +1,146 @@
+package shortestpaths
+
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestMatrix(t *testing.T) {
+	suite.Run(t, new(MatrixTestSuite))
+}
+
+type MatrixTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MatrixTestSuite) generateGraph(nodes []nodePrototype, costs float64) graph.Graph {
+	g := graph.NewGraph()
+
+	for _, n := range nodes {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: n.srcId},
+			T:    graph.Node{Id: n.targetId},
+			Cost: costs,
+		})
+	}
+
+	return g
+}
+
+func (suite *MatrixTestSuite) TestMatrix() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{2, 3},
+		{3, 4},
+		{4, 5},
+		{4, 6},
+		{5, 7},
+		{6, 7},
+	}, 2)
+
+	sources := []graph.Node{{Id: 1}, {Id: 4}, {Id: 7}}
+	targets := []graph.Node{{Id: 1}, {Id: 5}, {Id: 7}, {Id: 8}}
+	m, err := Matrix(g, sources, targets)
+	assert.NoError(t, err)
+
+	inf := math.Inf(0)
+	assert.Equal(t, [][]float64{
+		{0, 8, 10, inf},
+		{inf, 2, 4, inf},
+		{inf, inf, 0, inf},
+	}, m.Costs)
+
+	assert.Equal(t, 8.0, m.Cost(graph.Node{Id: 1}, graph.Node{Id: 5}))
+	assert.True(t, m.Reachable(graph.Node{Id: 4}, graph.Node{Id: 7}))
+	assert.False(t, m.Reachable(graph.Node{Id: 7}, graph.Node{Id: 1}))
+	assert.False(t, m.Reachable(graph.Node{Id: 2}, graph.Node{Id: 3})) // Not a source
+	assert.True(t, math.IsInf(m.Cost(graph.Node{Id: 1}, graph.Node{Id: 8}), 1))
+}
+
+// successorCountingGraph counts the nodes whose successors are explored
+type successorCountingGraph struct {
+	graph.Graph
+	explored int64
+}
+
+func (g *successorCountingGraph) Successors(n graph.Node) []graph.Node {
+	atomic.AddInt64(&g.explored, 1)
+	return g.Graph.Successors(n)
+}
+
+func (suite *MatrixTestSuite) TestStopsAtTargets() {
+	t := suite.T()
+	chain := []nodePrototype{}
+	for i := 1; i < 1000; i++ {
+		chain = append(chain, nodePrototype{i, i + 1})
+	}
+	g := &successorCountingGraph{Graph: suite.generateGraph(chain, 1)}
+
+	m, err := MatrixWorkers(g, []graph.Node{{Id: 1}, {Id: 2}}, []graph.Node{{Id: 3}, {Id: 4}}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{
+		{2, 3},
+		{1, 2},
+	}, m.Costs)
+	// Neither search goes past the last target
+	assert.True(t, atomic.LoadInt64(&g.explored) <= 6)
+}
+
+func (suite *MatrixTestSuite) TestNoTargets() {
+	t := suite.T()
+	chain := []nodePrototype{}
+	for i := 1; i < 1000; i++ {
+		chain = append(chain, nodePrototype{i, i + 1})
+	}
+	g := &successorCountingGraph{Graph: suite.generateGraph(chain, 1)}
+
+	m, err := MatrixWorkers(g, []graph.Node{{Id: 1}, {Id: 2}}, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{}, {}}, m.Costs)
+	assert.Equal(t, int64(0), atomic.LoadInt64(&g.explored))
+}
+
+func (suite *MatrixTestSuite) TestNegativeCost() {
+	t := suite.T()
+	g := suite.generateGraph([]nodePrototype{
+		{1, 2},
+		{3, 4},
+		{4, 5},
+	}, 2)
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 1}, T: graph.Node{Id: 3}, Cost: 1})
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 2}, T: graph.Node{Id: 3}, Cost: -5})
+
+	m, err := MatrixWorkers(g, []graph.Node{{Id: 1}, {Id: 2}, {Id: 3}}, []graph.Node{{Id: 5}}, 2)
+	assert.Equal(t, ErrContradiction, err)
+	assert.Nil(t, m)
+}
+
+func (suite *MatrixTestSuite) TestMatchesDijkstra() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(6))
+	g := randomGeometricGraph(rng, 100, 1200)
+	nodes := g.NodeList()
+	sources, targets := nodes[:20], nodes[10:40]
+
+	m, err := MatrixWorkers(g, sources, targets, 4)
+	assert.NoError(t, err)
+	for i, source := range sources {
+		for j, target := range targets {
+			p, err := DijkstraPath(g, source, target)
+			if err == ErrUnreachable {
+				assert.False(t, m.Reachable(source, target))
+				continue
+			}
+			assert.NoError(t, err)
+			assert.InEpsilon(t, p.Cost+1, m.Costs[i][j]+1, 1e-6)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package shortestpaths
+
+import (
+	"github.com/obeattie/vrp/graph"
+)
+
+type nodeHeapItem struct {
+	node     graph.Node
+	priority float64
+}
+
+// nodeHeap is an indexed binary min-heap of nodes, prioritised by cost. Unlike a plain priority queue, each node is
+// held at most once: pushing a node which is already queued lowers its priority in place (decrease-key).
+type nodeHeap struct {
+	items []nodeHeapItem
+	index map[graph.Node]int // Position of each queued node within items
+}
+
+func newNodeHeap() *nodeHeap {
+	return &nodeHeap{
+		index: map[graph.Node]int{},
+	}
+}
+
+func (h *nodeHeap) Len() int {
+	return len(h.items)
+}
+
+// Push queues n with the given priority. If n is already queued, its priority is lowered if the new priority is lower
+// (and left alone otherwise).
+func (h *nodeHeap) Push(n graph.Node, priority float64) {
+	if i, ok := h.index[n]; ok {
+		if priority < h.items[i].priority {
+			h.items[i].priority = priority
+			h.up(i)
+		}
+		return
+	}
+
+	h.items = append(h.items, nodeHeapItem{node: n, priority: priority})
+	h.index[n] = len(h.items) - 1
+	h.up(len(h.items) - 1)
+}
+
+// Priority returns the priority of n, if it is queued.
+func (h *nodeHeap) Priority(n graph.Node) (float64, bool) {
+	if i, ok := h.index[n]; ok {
+		return h.items[i].priority, true
+	}
+	return 0, false
+}
+
+// Pop removes and returns the node with the lowest priority.
+func (h *nodeHeap) Pop() (graph.Node, float64) {
+	top := h.items[0]
+	last := len(h.items) - 1
+	h.swap(0, last)
+	h.items = h.items[:last]
+	delete(h.index, top.node)
+	if last > 0 {
+		h.down(0)
+	}
+	return top.node, top.priority
+}
+
+// Peek returns the node with the lowest priority without removing it.
+func (h *nodeHeap) Peek() (graph.Node, float64) {
+	return h.items[0].node, h.items[0].priority
+}
+
+func (h *nodeHeap) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.index[h.items[i].node] = i
+	h.index[h.items[j].node] = j
+}
+
+func (h *nodeHeap) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if h.items[parent].priority <= h.items[i].priority {
+			break
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+func (h *nodeHeap) down(i int) {
+	for {
+		smallest := i
+		if l := 2*i + 1; l < len(h.items) && h.items[l].priority < h.items[smallest].priority {
+			smallest = l
+		}
+		if r := 2*i + 2; r < len(h.items) && h.items[r].priority < h.items[smallest].priority {
+			smallest = r
+		}
+		if smallest == i {
+			return
+		}
+		h.swap(i, smallest)
+		i = smallest
+	}
+}
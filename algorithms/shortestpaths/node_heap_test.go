@@ -0,0 +1,55 @@
+package shortestpaths
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestNodeHeapOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	h := newNodeHeap()
+	priorities := make([]float64, 1000)
+	for i := range priorities {
+		priorities[i] = rng.Float64() * 1e-9 // Tiny costs must still be ordered correctly
+		h.Push(graph.Node{Id: i + 1}, priorities[i])
+	}
+	sort.Float64s(priorities)
+
+	assert.Equal(t, len(priorities), h.Len())
+	for _, expected := range priorities {
+		_, peeked := h.Peek()
+		_, priority := h.Pop()
+		assert.Equal(t, expected, priority)
+		assert.Equal(t, peeked, priority)
+	}
+	assert.Equal(t, 0, h.Len())
+}
+
+func TestNodeHeapDecreaseKey(t *testing.T) {
+	h := newNodeHeap()
+	h.Push(graph.Node{Id: 1}, 5)
+	h.Push(graph.Node{Id: 2}, 3)
+	h.Push(graph.Node{Id: 3}, 4)
+
+	h.Push(graph.Node{Id: 1}, 1) // Decrease
+	h.Push(graph.Node{Id: 2}, 9) // Increases are ignored
+	assert.Equal(t, 3, h.Len())
+
+	priority, ok := h.Priority(graph.Node{Id: 2})
+	assert.True(t, ok)
+	assert.Equal(t, 3.0, priority)
+	_, ok = h.Priority(graph.Node{Id: 4})
+	assert.False(t, ok)
+
+	for _, expected := range []int{1, 2, 3} {
+		n, _ := h.Pop()
+		assert.Equal(t, expected, n.ID())
+		_, ok := h.Priority(n)
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,162 @@
+package shortestpaths
+
+import (
+	"sort"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// A ParetoPath is a path on the Pareto front of a multi-criteria search: no other path is at least as good in every
+// criterion and better in one.
+type ParetoPath struct {
+	Path
+	// Costs holds the total of each criterion along the path.
+	Costs []float64
+}
+
+// A CostsFunc returns the costs of an edge under each of several criteria (for example time, distance and tolls). It
+// must return the same number of costs for every edge.
+type CostsFunc func(e *graph.Edge) []float64
+
+// paretoLabel is a partial path found by ParetoPaths
+type paretoLabel struct {
+	node      graph.Node
+	costs     []float64
+	parent    *paretoLabel
+	dominated bool
+}
+
+// ParetoPaths returns the Pareto front of paths from source to target, where the cost of each edge is the vector
+// returned by costs. Paths are ordered lexicographically by their costs, so the first path is the best by the first
+// criterion. Where several paths have identical costs, only one of them is returned. If source and target are the
+// same, the front is the path of just that node, with every cost zero.
+//
+// This allows trade-offs to be made between the criteria: choosing between the fastest and the cheapest route, say.
+//
+// The search is label-correcting, and every cost must be non-negative. If any is negative, ErrContradiction is
+// returned. The front can grow exponentially with the size of the graph, so it's best kept to two or three criteria.
+func ParetoPaths(g graph.Graph, source, target graph.Node, costs CostsFunc) ([]ParetoPath, error) {
+	labels := map[graph.Node][]*paretoLabel{} // The non-dominated labels at each node
+	fringe := []*paretoLabel{}                // FIFO
+
+	// add records a new label at its node, unless it is dominated there or by a path already found to the target (which
+	// no extension could then improve upon)
+	add := func(l *paretoLabel) {
+		for _, other := range labels[target] {
+			if weaklyDominates(other.costs, l.costs) {
+				return
+			}
+		}
+		existing := labels[l.node]
+		for _, other := range existing {
+			if weaklyDominates(other.costs, l.costs) {
+				return
+			}
+		}
+		kept := existing[:0]
+		for _, other := range existing {
+			if weaklyDominates(l.costs, other.costs) {
+				other.dominated = true
+			} else {
+				kept = append(kept, other)
+			}
+		}
+		labels[l.node] = append(kept, l)
+		if l.node != target {
+			fringe = append(fringe, l)
+		}
+	}
+
+	labels[source] = []*paretoLabel{{node: source, costs: make([]float64, criteria(g, source, costs))}}
+	fringe = append(fringe, labels[source][0])
+	if source == target {
+		fringe = fringe[:0]
+	}
+	for len(fringe) > 0 {
+		l := fringe[0]
+		fringe[0] = nil
+		fringe = fringe[1:]
+		if l.dominated {
+			continue
+		}
+
+		for _, w := range g.Successors(l.node) {
+			edgeCosts := costs(g.EdgeTo(l.node, w))
+			next := &paretoLabel{
+				node:   w,
+				costs:  make([]float64, len(edgeCosts)),
+				parent: l,
+			}
+			for i, c := range edgeCosts {
+				if c < 0 {
+					return nil, ErrContradiction
+				}
+				next.costs[i] = l.costs[i] + c
+			}
+			add(next)
+		}
+	}
+
+	front := labels[target]
+	if len(front) == 0 {
+		return nil, ErrUnreachable
+	}
+	result := make([]ParetoPath, len(front))
+	for i, l := range front {
+		nodes := []graph.Node{}
+		for v := l; v != nil; v = v.parent {
+			nodes = append(nodes, v.node)
+		}
+		for j, k := 0, len(nodes)-1; j < k; j, k = j+1, k-1 {
+			nodes[j], nodes[k] = nodes[k], nodes[j]
+		}
+		path, err := NewPath(g, nodes)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = ParetoPath{Path: path, Costs: l.costs}
+	}
+	sort.Sort(paretoPathsByCosts(result))
+	return result, nil
+}
+
+// criteria returns the number of costs returned for each edge, found from the first edge in the graph (checking those
+// leaving source first). It is zero if the graph has no edges.
+func criteria(g graph.Graph, source graph.Node, costs CostsFunc) int {
+	for _, n := range append([]graph.Node{source}, g.NodeList()...) {
+		for _, w := range g.Successors(n) {
+			return len(costs(g.EdgeTo(n, w)))
+		}
+	}
+	return 0
+}
+
+// weaklyDominates returns whether cost vector a is no worse than b in every criterion.
+func weaklyDominates(a, b []float64) bool {
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// paretoPathsByCosts sorts paths lexicographically by their costs
+type paretoPathsByCosts []ParetoPath
+
+func (p paretoPathsByCosts) Len() int {
+	return len(p)
+}
+
+func (p paretoPathsByCosts) Less(i, j int) bool {
+	for k := range p[i].Costs {
+		if p[i].Costs[k] != p[j].Costs[k] {
+			return p[i].Costs[k] < p[j].Costs[k]
+		}
+	}
+	return false
+}
+
+func (p paretoPathsByCosts) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
@@ -0,0 +1,179 @@
+package shortestpaths
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestParetoPaths(t *testing.T) {
+	suite.Run(t, new(ParetoPathsTestSuite))
+}
+
+type ParetoPathsTestSuite struct {
+	suite.Suite
+}
+
+// tolls are keyed by edge (head and tail ID)
+func (suite *ParetoPathsTestSuite) timeAndTolls(tolls map[[2]int]float64) CostsFunc {
+	return func(e *graph.Edge) []float64 {
+		return []float64{e.Cost, tolls[[2]int{e.H.ID(), e.T.ID()}]}
+	}
+}
+
+func (suite *ParetoPathsTestSuite) TestTradeOff() {
+	t := suite.T()
+	g := graph.NewGraph()
+	for _, e := range []costedEdgePrototype{
+		{1, 2, 1}, // Motorway: fast, but tolled
+		{2, 5, 1},
+		{1, 3, 2}, // Trunk road: a cheaper toll
+		{3, 5, 2},
+		{1, 4, 4}, // Back roads: free
+		{4, 5, 4},
+		{1, 5, 10}, // Slow and tolled: dominated
+	} {
+		g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: e.srcId}, T: graph.Node{Id: e.targetId}, Cost: e.cost})
+	}
+	tolls := map[[2]int]float64{
+		{1, 2}: 5,
+		{1, 3}: 2,
+		{1, 5}: 5,
+	}
+
+	paths, err := ParetoPaths(g, graph.Node{Id: 1}, graph.Node{Id: 5}, suite.timeAndTolls(tolls))
+	assert.NoError(t, err)
+	assert.Len(t, paths, 3)
+	assert.Equal(t, []float64{2, 5}, paths[0].Costs)
+	assert.Equal(t, []graph.Node{{Id: 1}, {Id: 2}, {Id: 5}}, paths[0].Nodes)
+	assert.Equal(t, 2.0, paths[0].Cost)
+	assert.Len(t, paths[0].Edges, 2)
+	assert.Equal(t, []float64{4, 2}, paths[1].Costs)
+	assert.Equal(t, []graph.Node{{Id: 1}, {Id: 3}, {Id: 5}}, paths[1].Nodes)
+	assert.Equal(t, []float64{8, 0}, paths[2].Costs)
+	assert.Equal(t, []graph.Node{{Id: 1}, {Id: 4}, {Id: 5}}, paths[2].Nodes)
+
+	// With a single criterion, the front is the shortest path
+	paths, err = ParetoPaths(g, graph.Node{Id: 1}, graph.Node{Id: 5}, func(e *graph.Edge) []float64 {
+		return []float64{e.Cost}
+	})
+	assert.NoError(t, err)
+	assert.Len(t, paths, 1)
+	assert.Equal(t, []graph.Node{{Id: 1}, {Id: 2}, {Id: 5}}, paths[0].Nodes)
+}
+
+func (suite *ParetoPathsTestSuite) TestErrors() {
+	t := suite.T()
+	g := graph.NewGraph()
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 1}, T: graph.Node{Id: 2}, Cost: 1})
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 2}, T: graph.Node{Id: 3}, Cost: -1})
+	g.AddNode(graph.Node{Id: 4})
+
+	_, err := ParetoPaths(g, graph.Node{Id: 1}, graph.Node{Id: 4}, suite.timeAndTolls(nil))
+	assert.Equal(t, ErrContradiction, err)
+	_, err = ParetoPaths(g, graph.Node{Id: 3}, graph.Node{Id: 4}, suite.timeAndTolls(nil))
+	assert.Equal(t, ErrUnreachable, err)
+}
+
+func (suite *ParetoPathsTestSuite) TestSameSourceAndTarget() {
+	t := suite.T()
+	g := graph.NewGraph()
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 1}, T: graph.Node{Id: 2}, Cost: 1})
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 2}, T: graph.Node{Id: 1}, Cost: 1})
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 3}, T: graph.Node{Id: 1}, Cost: 1})
+
+	for _, n := range []graph.Node{{Id: 1}, {Id: 3}} {
+		paths, err := ParetoPaths(g, n, n, suite.timeAndTolls(nil))
+		assert.NoError(t, err)
+		if assert.Len(t, paths, 1) {
+			assert.Equal(t, []graph.Node{n}, paths[0].Nodes)
+			assert.Equal(t, []float64{0, 0}, paths[0].Costs)
+			assert.Equal(t, 0.0, paths[0].Cost)
+		}
+	}
+}
+
+func (suite *ParetoPathsTestSuite) TestZeroCostCycle() {
+	t := suite.T()
+	g := graph.NewGraph()
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 1}, T: graph.Node{Id: 2}, Cost: 0})
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 2}, T: graph.Node{Id: 1}, Cost: 0})
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 2}, T: graph.Node{Id: 3}, Cost: 1})
+
+	paths, err := ParetoPaths(g, graph.Node{Id: 1}, graph.Node{Id: 3}, suite.timeAndTolls(nil))
+	assert.NoError(t, err)
+	assert.Len(t, paths, 1)
+	assert.Equal(t, []graph.Node{{Id: 1}, {Id: 2}, {Id: 3}}, paths[0].Nodes)
+}
+
+func (suite *ParetoPathsTestSuite) TestMatchesEnumeration() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(13))
+
+	for i := 0; i < 20; i++ {
+		g := graph.NewGraph()
+		tolls := map[[2]int]float64{}
+		for j := 1; j <= 8; j++ {
+			g.AddNode(graph.Node{Id: j})
+		}
+		for j := 0; j < 24; j++ {
+			h, tail := 1+rng.Intn(8), 1+rng.Intn(8)
+			if h != tail {
+				g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: h}, T: graph.Node{Id: tail}, Cost: float64(1 + rng.Intn(10))})
+				tolls[[2]int{h, tail}] = float64(rng.Intn(10))
+			}
+		}
+		costs := suite.timeAndTolls(tolls)
+
+		paths, err := ParetoPaths(g, graph.Node{Id: 1}, graph.Node{Id: 8}, costs)
+
+		// With positive times, every path on the front is simple, so enumerating simple paths finds it
+		var all [][2]float64
+		var explore func(n graph.Node, visited map[int]bool, total [2]float64)
+		explore = func(n graph.Node, visited map[int]bool, total [2]float64) {
+			if n.ID() == 8 {
+				all = append(all, total)
+				return
+			}
+			for _, w := range g.Successors(n) {
+				if !visited[w.ID()] {
+					visited[w.ID()] = true
+					c := costs(g.EdgeTo(n, w))
+					explore(w, visited, [2]float64{total[0] + c[0], total[1] + c[1]})
+					delete(visited, w.ID())
+				}
+			}
+		}
+		explore(graph.Node{Id: 1}, map[int]bool{1: true}, [2]float64{})
+		front := map[[2]float64]bool{}
+		for _, a := range all {
+			dominated := false
+			for _, b := range all {
+				if b[0] <= a[0] && b[1] <= a[1] && b != a {
+					dominated = true
+				}
+			}
+			if !dominated {
+				front[a] = true
+			}
+		}
+
+		if len(front) == 0 {
+			assert.Equal(t, ErrUnreachable, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Len(t, paths, len(front))
+		for j, p := range paths {
+			assert.True(t, front[[2]float64{p.Costs[0], p.Costs[1]}])
+			assert.Equal(t, p.Costs[0], p.Cost)
+			if j > 0 {
+				assert.True(t, paths[j-1].Costs[0] < p.Costs[0])
+			}
+		}
+	}
+}
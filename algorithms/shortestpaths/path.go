@@ -0,0 +1,66 @@
+package shortestpaths
+
+import (
+	"time"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// A Path is a walk through a graph, returned by each of the shortest path algorithms.
+type Path struct {
+	// Nodes are the nodes visited by the path, in order, including the source and target.
+	Nodes []graph.Node
+	// Edges are the edges traversed by the path, in order. There is always one fewer edge than there are nodes.
+	Edges []*graph.Edge
+	// Cost is the total cost of the edges traversed.
+	Cost float64
+}
+
+// NewPath returns a Path visiting the given nodes, with edges and costs taken from g. If any consecutive pair of nodes
+// is not joined by an edge, ErrUnreachable is returned.
+func NewPath(g graph.Graph, nodes []graph.Node) (Path, error) {
+	p := Path{
+		Nodes: nodes,
+		Edges: make([]*graph.Edge, 0, len(nodes)),
+	}
+	for i := 1; i < len(nodes); i++ {
+		edge := g.EdgeTo(nodes[i-1], nodes[i])
+		if edge == nil {
+			return Path{}, ErrUnreachable
+		}
+		p.Edges = append(p.Edges, edge)
+		p.Cost += edge.Cost
+	}
+	return p, nil
+}
+
+// Len returns the number of edges traversed by the path.
+func (p Path) Len() int {
+	return len(p.Edges)
+}
+
+// Duration returns the cost of the path as a time duration, treating edge costs as milliseconds (as in the graphs
+// returned by route.Route.Graph).
+func (p Path) Duration() time.Duration {
+	return time.Duration(p.Cost * float64(time.Millisecond))
+}
+
+// Source returns the first node in the path.
+func (p Path) Source() graph.Node {
+	if len(p.Nodes) == 0 {
+		return graph.Node{}
+	}
+	return p.Nodes[0]
+}
+
+// Target returns the last node in the path.
+func (p Path) Target() graph.Node {
+	if len(p.Nodes) == 0 {
+		return graph.Node{}
+	}
+	return p.Nodes[len(p.Nodes)-1]
+}
+
+func (p Path) IsZero() bool {
+	return len(p.Nodes) == 0
+}
@@ -0,0 +1,89 @@
+package shortestpaths
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestPath(t *testing.T) {
+	suite.Run(t, new(PathTestSuite))
+}
+
+type PathTestSuite struct {
+	suite.Suite
+	g graph.Graph
+}
+
+func (suite *PathTestSuite) SetupTest() {
+	g := graph.NewGraph()
+	edges := [...]struct {
+		srcId, targetId int
+		cost            float64
+	}{
+		{1, 2, 1500},
+		{2, 3, 500},
+		{3, 4, 250},
+	}
+
+	for _, e := range edges {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: e.srcId},
+			T:    graph.Node{Id: e.targetId},
+			Cost: e.cost,
+		})
+	}
+
+	suite.g = g
+}
+
+func (suite *PathTestSuite) TestNewPath() {
+	t, g := suite.T(), suite.g
+
+	p, err := NewPath(g, []graph.Node{{Id: 1}, {Id: 2}, {Id: 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2000.0, p.Cost)
+	assert.Equal(t, 2, p.Len())
+	assert.Len(t, p.Edges, 2)
+	assert.Equal(t, 1, p.Edges[0].H.ID())
+	assert.Equal(t, 2, p.Edges[0].T.ID())
+	assert.Equal(t, 2, p.Edges[1].H.ID())
+	assert.Equal(t, 3, p.Edges[1].T.ID())
+	assert.Equal(t, 1, p.Source().ID())
+	assert.Equal(t, 3, p.Target().ID())
+	assert.Equal(t, 2*time.Second, p.Duration())
+	assert.False(t, p.IsZero())
+}
+
+func (suite *PathTestSuite) TestNewPathSingleNode() {
+	t, g := suite.T(), suite.g
+
+	p, err := NewPath(g, []graph.Node{{Id: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, p.Cost)
+	assert.Equal(t, 0, p.Len())
+	assert.Equal(t, p.Source(), p.Target())
+	assert.False(t, p.IsZero())
+}
+
+func (suite *PathTestSuite) TestNewPathDisconnected() {
+	t, g := suite.T(), suite.g
+
+	p, err := NewPath(g, []graph.Node{{Id: 1}, {Id: 3}})
+	assert.Equal(t, ErrUnreachable, err)
+	assert.True(t, p.IsZero())
+}
+
+func (suite *PathTestSuite) TestZeroPath() {
+	t := suite.T()
+
+	p := Path{}
+	assert.True(t, p.IsZero())
+	assert.Equal(t, 0, p.Len())
+	assert.True(t, p.Source().IsZero())
+	assert.True(t, p.Target().IsZero())
+}
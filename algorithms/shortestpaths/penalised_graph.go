@@ -0,0 +1,69 @@
+package shortestpaths
+
+import (
+	"github.com/obeattie/vrp/graph"
+)
+
+// penalisedGraph is a read-only view of a graph in which the costs of some edges are multiplied by a penalty, steering
+// searches away from them.
+type penalisedGraph struct {
+	graph.ReadOnly
+	penalties map[[2]int]float64 // By (head, tail) node IDs
+}
+
+func newPenalisedGraph(g graph.Graph) *penalisedGraph {
+	return &penalisedGraph{
+		ReadOnly:  graph.ReadOnly{Graph: g},
+		penalties: map[[2]int]float64{},
+	}
+}
+
+// penalise multiplies the cost of the edge from head to tail by factor (on top of any existing penalty).
+func (g *penalisedGraph) penalise(head, tail graph.Node, factor float64) {
+	k := [2]int{head.ID(), tail.ID()}
+	if p, ok := g.penalties[k]; ok {
+		g.penalties[k] = p * factor
+	} else {
+		g.penalties[k] = factor
+	}
+}
+
+func (g *penalisedGraph) penalised(e *graph.Edge) *graph.Edge {
+	if e == nil {
+		return nil
+	}
+	if p, ok := g.penalties[[2]int{e.H.ID(), e.T.ID()}]; ok {
+		return &graph.Edge{
+			H:        e.H,
+			T:        e.T,
+			Cost:     e.Cost * p,
+			Capacity: e.Capacity,
+		}
+	}
+	return e
+}
+
+func (g *penalisedGraph) EdgeBetween(n, neighbour graph.Node) *graph.Edge {
+	return g.penalised(g.Graph.EdgeBetween(n, neighbour))
+}
+
+func (g *penalisedGraph) EdgeTo(n, successor graph.Node) *graph.Edge {
+	return g.penalised(g.Graph.EdgeTo(n, successor))
+}
+
+// Cost returns the penalised cost of e, which must be an edge of the underlying graph: edges returned by this view are
+// already penalised, and would be penalised again.
+func (g *penalisedGraph) Cost(e *graph.Edge) float64 {
+	if e == nil {
+		return g.Graph.Cost(nil)
+	}
+	if p, ok := g.penalties[[2]int{e.H.ID(), e.T.ID()}]; ok {
+		return e.Cost * p
+	}
+	return g.Graph.Cost(e)
+}
+
+// Copy returns a mutable copy of the graph, with penalties applied to its costs.
+func (g *penalisedGraph) Copy() graph.Graph {
+	return graph.CopyOf(g)
+}
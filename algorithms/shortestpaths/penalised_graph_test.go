@@ -0,0 +1,61 @@
+package shortestpaths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestPenalisedGraph(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 1}, T: graph.Node{Id: 2}, Cost: 2})
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 2}, T: graph.Node{Id: 3}, Cost: 2})
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 1}, T: graph.Node{Id: 3}, Cost: 5})
+	p := newPenalisedGraph(g)
+
+	path, err := DijkstraPath(p, graph.Node{Id: 1}, graph.Node{Id: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, path.Cost)
+
+	p.penalise(graph.Node{Id: 1}, graph.Node{Id: 2}, 1.5)
+	assert.Equal(t, 3.0, p.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 2}).Cost)
+	p.penalise(graph.Node{Id: 1}, graph.Node{Id: 2}, 2)
+	assert.Equal(t, 6.0, p.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 2}).Cost)
+	assert.Equal(t, 6.0, p.EdgeBetween(graph.Node{Id: 2}, graph.Node{Id: 1}).Cost)
+	assert.Equal(t, 6.0, p.Cost(g.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 2})))
+	assert.Nil(t, p.EdgeTo(graph.Node{Id: 3}, graph.Node{Id: 1}))
+
+	path, err = DijkstraPath(p, graph.Node{Id: 1}, graph.Node{Id: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, path.Cost)
+
+	c := p.Copy()
+	assert.Equal(t, 6.0, c.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 2}).Cost)
+	assert.Panics(t, func() { p.AddNode(graph.Node{Id: 4}) })
+	assert.Equal(t, 2.0, g.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 2}).Cost) // The underlying graph is untouched
+}
+
+func TestPenalisedGraphCost(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 1}, T: graph.Node{Id: 2}, Cost: 2})
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 2}, T: graph.Node{Id: 3}, Cost: 3})
+	p := newPenalisedGraph(g)
+	p.penalise(graph.Node{Id: 1}, graph.Node{Id: 2}, 3)
+
+	// Edges of the underlying graph are penalised
+	assert.Equal(t, 6.0, p.Cost(g.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 2})))
+	assert.Equal(t, 3.0, p.Cost(g.EdgeTo(graph.Node{Id: 2}, graph.Node{Id: 3})))
+	assert.True(t, math.IsInf(p.Cost(nil), 1))
+
+	path, err := DijkstraPath(p, graph.Node{Id: 1}, graph.Node{Id: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 9.0, path.Cost)
+	cost := 0.0
+	for _, e := range path.Edges {
+		cost += p.Cost(g.EdgeTo(e.H, e.T))
+	}
+	assert.Equal(t, 9.0, cost)
+}
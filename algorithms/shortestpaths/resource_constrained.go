@@ -0,0 +1,279 @@
+package shortestpaths
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// A Label is a partial path found by a resource-constrained search, along with the resources it has consumed.
+type Label struct {
+	// Node is the last node on the path.
+	Node graph.Node
+	// Cost is the total cost of the edges traversed.
+	Cost float64
+	// Resources holds the resources consumed on arrival at Node.
+	Resources []float64
+	parent    *Label
+	visited   map[int]bool // By node ID
+	dominated bool
+}
+
+// Nodes returns the nodes along the label's path, from the source to Node.
+func (l *Label) Nodes() []graph.Node {
+	result := []graph.Node{}
+	for ; l != nil; l = l.parent {
+		result = append(result, l.Node)
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+// Visited returns whether the label's path visits the given node.
+func (l *Label) Visited(n graph.Node) bool {
+	return l.visited[n.ID()]
+}
+
+// An ExtensionFunc extends a label's resources along an edge, returning the resources consumed on arrival at the
+// edge's tail and whether doing so is feasible. It must not modify the resources it is passed.
+type ExtensionFunc func(resources []float64, e *graph.Edge) ([]float64, bool)
+
+// A DominanceFunc returns whether label a dominates label b, which is at the same node: that is, whether no extension
+// of b can ever be better than the same extension of a. Dominated labels are discarded.
+type DominanceFunc func(a, b *Label) bool
+
+// ResourceConstraints configure a resource-constrained shortest path search.
+type ResourceConstraints struct {
+	// Initial holds the resources consumed at the source.
+	Initial []float64
+	// Extend extends resources along each edge. It is required.
+	Extend ExtensionFunc
+	// Dominates decides which labels to discard. If nil, Dominance is used.
+	Dominates DominanceFunc
+	// Cost returns the cost of an edge (for example, a reduced cost in column generation). If nil, edge costs are used.
+	Cost func(e *graph.Edge) float64
+}
+
+// Dominance is the default DominanceFunc. Label a dominates label b if it is no more costly, has consumed no more of
+// any resource, and has visited a subset of the nodes b has.
+func Dominance(a, b *Label) bool {
+	if a.Cost > b.Cost || len(a.visited) > len(b.visited) {
+		return false
+	}
+	for i, r := range a.Resources {
+		if r > b.Resources[i] {
+			return false
+		}
+	}
+	for id := range a.visited {
+		if !b.visited[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// ResourceConstrainedPaths solves the elementary shortest path problem with resource constraints (ESPPRC): it returns
+// the labels at the target which are Pareto-optimal in cost and resources, in increasing order of cost. Each label
+// represents a path from source to target which visits no node more than once, and along which every resource
+// extension is feasible.
+//
+// Edge costs may be negative. Labels are extended in order of their first resource (if any), so the search is most
+// efficient when that resource increases along every edge, as time does.
+//
+// If no feasible path exists, ErrUnreachable is returned.
+func ResourceConstrainedPaths(g graph.Graph, source, target graph.Node, c ResourceConstraints) ([]*Label, error) {
+	dominates := c.Dominates
+	if dominates == nil {
+		dominates = Dominance
+	}
+	cost := c.Cost
+	if cost == nil {
+		cost = func(e *graph.Edge) float64 { return e.Cost }
+	}
+
+	labels := map[graph.Node][]*Label{} // The non-dominated labels at each node
+	fringe := &labelQueue{}
+	// add records a new label at its node, unless it is dominated
+	add := func(l *Label) {
+		existing := labels[l.Node]
+		for _, other := range existing {
+			if dominates(other, l) {
+				return
+			}
+		}
+		kept := existing[:0]
+		for _, other := range existing {
+			if dominates(l, other) {
+				other.dominated = true
+			} else {
+				kept = append(kept, other)
+			}
+		}
+		labels[l.Node] = append(kept, l)
+		if l.Node != target { // Labels at the target are complete
+			heap.Push(fringe, l)
+		}
+	}
+
+	add(&Label{
+		Node:      source,
+		Resources: append([]float64(nil), c.Initial...),
+		visited:   map[int]bool{source.ID(): true},
+	})
+	for fringe.Len() > 0 {
+		l := heap.Pop(fringe).(*Label)
+		if l.dominated {
+			continue
+		}
+
+		for _, w := range g.Successors(l.Node) {
+			if l.visited[w.ID()] {
+				continue
+			}
+			edge := g.EdgeTo(l.Node, w)
+			resources, ok := c.Extend(l.Resources, edge)
+			if !ok {
+				continue
+			}
+
+			visited := make(map[int]bool, len(l.visited)+1)
+			for id := range l.visited {
+				visited[id] = true
+			}
+			visited[w.ID()] = true
+			add(&Label{
+				Node:      w,
+				Cost:      l.Cost + cost(edge),
+				Resources: resources,
+				parent:    l,
+				visited:   visited,
+			})
+		}
+	}
+
+	// Which nodes were visited no longer matters once the target is reached
+	result := make([]*Label, 0, len(labels[target]))
+	for _, l := range labels[target] {
+		dominated := false
+		for _, other := range labels[target] {
+			if other != l && paretoDominates(other, l) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			result = append(result, l)
+		}
+	}
+	if len(result) == 0 {
+		return nil, ErrUnreachable
+	}
+	sort.Stable(labelsByCost(result))
+	return result, nil
+}
+
+// paretoDominates returns whether label a is no worse than b in cost and every resource, and strictly better in at
+// least one.
+func paretoDominates(a, b *Label) bool {
+	better := a.Cost < b.Cost
+	if a.Cost > b.Cost {
+		return false
+	}
+	for i, r := range a.Resources {
+		if r > b.Resources[i] {
+			return false
+		} else if r < b.Resources[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// ConsumptionExtension returns an ExtensionFunc which adds the consumption of each edge to resource i, and which is
+// infeasible once the total exceeds limit. It suits resources such as load (with the demand of each node charged to
+// the edges entering it) or total duration.
+func ConsumptionExtension(i int, consumption func(e *graph.Edge) float64, limit float64) ExtensionFunc {
+	return func(resources []float64, e *graph.Edge) ([]float64, bool) {
+		result := append([]float64(nil), resources...)
+		result[i] += consumption(e)
+		return result, result[i] <= limit
+	}
+}
+
+// TimeWindowExtension returns an ExtensionFunc which treats resource i as time, advancing it by the travel time of
+// each edge. Nodes may have time windows (by node ID): arriving before a window opens means waiting until it does, and
+// arriving after it closes is infeasible.
+func TimeWindowExtension(i int, travelTime func(e *graph.Edge) float64, windows map[int][2]float64) ExtensionFunc {
+	return func(resources []float64, e *graph.Edge) ([]float64, bool) {
+		result := append([]float64(nil), resources...)
+		result[i] += travelTime(e)
+		if window, ok := windows[e.T.ID()]; ok {
+			result[i] = math.Max(result[i], window[0])
+			return result, result[i] <= window[1]
+		}
+		return result, true
+	}
+}
+
+// ChainExtensions returns an ExtensionFunc which applies each of the given functions in turn, and is feasible only if
+// all of them are.
+func ChainExtensions(extensions ...ExtensionFunc) ExtensionFunc {
+	return func(resources []float64, e *graph.Edge) ([]float64, bool) {
+		for _, extend := range extensions {
+			var ok bool
+			if resources, ok = extend(resources, e); !ok {
+				return nil, false
+			}
+		}
+		return resources, true
+	}
+}
+
+// labelQueue is a min-heap of labels ordered by their first resource, then cost, for use with container/heap.
+type labelQueue []*Label
+
+func (q labelQueue) Len() int {
+	return len(q)
+}
+
+func (q labelQueue) Less(i, j int) bool {
+	if len(q[i].Resources) > 0 && q[i].Resources[0] != q[j].Resources[0] {
+		return q[i].Resources[0] < q[j].Resources[0]
+	}
+	return q[i].Cost < q[j].Cost
+}
+
+func (q labelQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *labelQueue) Push(x interface{}) {
+	*q = append(*q, x.(*Label))
+}
+
+func (q *labelQueue) Pop() interface{} {
+	old := *q
+	l := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return l
+}
+
+// labelsByCost sorts labels in increasing order of cost
+type labelsByCost []*Label
+
+func (l labelsByCost) Len() int {
+	return len(l)
+}
+
+func (l labelsByCost) Less(i, j int) bool {
+	return l[i].Cost < l[j].Cost
+}
+
+func (l labelsByCost) Swap(i, j int) {
+	l[i], l[j] = l[j], l[i]
+}
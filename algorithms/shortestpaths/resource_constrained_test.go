@@ -0,0 +1,219 @@
+package shortestpaths
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestResourceConstrainedPaths(t *testing.T) {
+	suite.Run(t, new(ResourceConstrainedPathsTestSuite))
+}
+
+type ResourceConstrainedPathsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ResourceConstrainedPathsTestSuite) generateGraph(edges []costedEdgePrototype) graph.Graph {
+	g := graph.NewGraph()
+
+	for _, e := range edges {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: e.srcId},
+			T:    graph.Node{Id: e.targetId},
+			Cost: e.cost,
+		})
+	}
+
+	return g
+}
+
+func (suite *ResourceConstrainedPathsTestSuite) ids(nodes []graph.Node) []int {
+	result := make([]int, len(nodes))
+	for i, n := range nodes {
+		result[i] = n.ID()
+	}
+	return result
+}
+
+func (suite *ResourceConstrainedPathsTestSuite) TestTimeWindows() {
+	t := suite.T()
+	// The cheapest path (1, 2, 4) arrives at 2 too late
+	g := suite.generateGraph([]costedEdgePrototype{
+		{1, 2, 1},
+		{2, 4, 1},
+		{1, 3, 2},
+		{3, 4, 2},
+	})
+	travelTime := func(e *graph.Edge) float64 { return e.Cost * 10 }
+	windows := map[int][2]float64{
+		2: {0, 5},
+		3: {30, 40}, // Arriving early means waiting
+	}
+
+	labels, err := ResourceConstrainedPaths(g, graph.Node{Id: 1}, graph.Node{Id: 4}, ResourceConstraints{
+		Initial: []float64{0},
+		Extend:  TimeWindowExtension(0, travelTime, windows),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, labels, 1)
+	assert.Equal(t, []int{1, 3, 4}, suite.ids(labels[0].Nodes()))
+	assert.Equal(t, 4.0, labels[0].Cost)
+	assert.Equal(t, []float64{50}, labels[0].Resources)
+	assert.True(t, labels[0].Visited(graph.Node{Id: 3}))
+	assert.False(t, labels[0].Visited(graph.Node{Id: 2}))
+
+	windows[3] = [2]float64{0, 10}
+	labels, err = ResourceConstrainedPaths(g, graph.Node{Id: 1}, graph.Node{Id: 4}, ResourceConstraints{
+		Initial: []float64{0},
+		Extend:  TimeWindowExtension(0, travelTime, windows),
+	})
+	assert.Equal(t, ErrUnreachable, err)
+	assert.Nil(t, labels)
+}
+
+func (suite *ResourceConstrainedPathsTestSuite) TestParetoFront() {
+	t := suite.T()
+	// Negative (reduced) costs encourage long paths, which load restricts
+	g := suite.generateGraph([]costedEdgePrototype{
+		{1, 2, -2},
+		{2, 3, -2},
+		{3, 5, 1},
+		{1, 4, -1},
+		{4, 5, 1},
+		{1, 5, 2},
+		{3, 1, -10}, // Never usable: paths are elementary
+	})
+	load := func(e *graph.Edge) float64 { return 1 }
+
+	labels, err := ResourceConstrainedPaths(g, graph.Node{Id: 1}, graph.Node{Id: 5}, ResourceConstraints{
+		Initial: []float64{0},
+		Extend:  ConsumptionExtension(0, load, 3),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, labels, 3)
+	assert.Equal(t, []int{1, 2, 3, 5}, suite.ids(labels[0].Nodes()))
+	assert.Equal(t, -3.0, labels[0].Cost)
+	assert.Equal(t, []int{1, 4, 5}, suite.ids(labels[1].Nodes()))
+	assert.Equal(t, 0.0, labels[1].Cost)
+	assert.Equal(t, []int{1, 5}, suite.ids(labels[2].Nodes()))
+	assert.Equal(t, 2.0, labels[2].Cost)
+
+	labels, err = ResourceConstrainedPaths(g, graph.Node{Id: 1}, graph.Node{Id: 5}, ResourceConstraints{
+		Initial: []float64{0},
+		Extend:  ConsumptionExtension(0, load, 2),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+	assert.Equal(t, []int{1, 4, 5}, suite.ids(labels[0].Nodes()))
+}
+
+func (suite *ResourceConstrainedPathsTestSuite) TestCustomCostAndDominance() {
+	t := suite.T()
+	g := suite.generateGraph([]costedEdgePrototype{
+		{1, 2, 1},
+		{2, 3, 1},
+		{1, 3, 5},
+	})
+
+	labels, err := ResourceConstrainedPaths(g, graph.Node{Id: 1}, graph.Node{Id: 3}, ResourceConstraints{
+		Extend: func(r []float64, e *graph.Edge) ([]float64, bool) { return r, true },
+		Cost:   func(e *graph.Edge) float64 { return -e.Cost },
+		Dominates: func(a, b *Label) bool {
+			return a.Cost <= b.Cost
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, labels, 1)
+	assert.Equal(t, []int{1, 3}, suite.ids(labels[0].Nodes()))
+	assert.Equal(t, -5.0, labels[0].Cost)
+}
+
+func (suite *ResourceConstrainedPathsTestSuite) TestMatchesEnumeration() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(12))
+
+	for i := 0; i < 20; i++ {
+		g := graph.NewGraph()
+		for j := 1; j <= 7; j++ {
+			g.AddNode(graph.Node{Id: j})
+		}
+		for j := 0; j < 20; j++ {
+			h, tail := 1+rng.Intn(7), 1+rng.Intn(7)
+			if h != tail {
+				g.AddDirectedEdge(&graph.Edge{
+					H:    graph.Node{Id: h},
+					T:    graph.Node{Id: tail},
+					Cost: float64(rng.Intn(10) - 5),
+				})
+			}
+		}
+		windows := map[int][2]float64{}
+		demand := map[int]float64{}
+		for j := 1; j <= 7; j++ {
+			start := float64(rng.Intn(20))
+			windows[j] = [2]float64{start, start + float64(rng.Intn(30))}
+			demand[j] = float64(rng.Intn(4))
+		}
+		windows[1] = [2]float64{0, 100}
+		extend := ChainExtensions(
+			TimeWindowExtension(0, func(e *graph.Edge) float64 { return 5 }, windows),
+			ConsumptionExtension(1, func(e *graph.Edge) float64 { return demand[e.T.ID()] }, 6))
+
+		labels, err := ResourceConstrainedPaths(g, graph.Node{Id: 1}, graph.Node{Id: 7}, ResourceConstraints{
+			Initial: []float64{0, 0},
+			Extend:  extend,
+		})
+
+		// Enumerate every feasible elementary path by brute force, and find the Pareto front
+		var feasible []*Label
+		var explore func(l *Label)
+		explore = func(l *Label) {
+			if l.Node.ID() == 7 {
+				feasible = append(feasible, l)
+				return
+			}
+			for _, w := range g.Successors(l.Node) {
+				if l.visited[w.ID()] {
+					continue
+				}
+				edge := g.EdgeTo(l.Node, w)
+				if resources, ok := extend(l.Resources, edge); ok {
+					visited := map[int]bool{w.ID(): true}
+					for id := range l.visited {
+						visited[id] = true
+					}
+					explore(&Label{Node: w, Cost: l.Cost + edge.Cost, Resources: resources, parent: l, visited: visited})
+				}
+			}
+		}
+		explore(&Label{Node: graph.Node{Id: 1}, Resources: []float64{0, 0}, visited: map[int]bool{1: true}})
+		front := map[[3]float64]bool{}
+		for _, l := range feasible {
+			dominated := false
+			for _, other := range feasible {
+				if paretoDominates(other, l) {
+					dominated = true
+				}
+			}
+			if !dominated {
+				front[[3]float64{l.Cost, l.Resources[0], l.Resources[1]}] = true
+			}
+		}
+
+		if len(front) == 0 {
+			assert.Equal(t, ErrUnreachable, err)
+			continue
+		}
+		assert.NoError(t, err)
+		returned := map[[3]float64]bool{}
+		for _, l := range labels {
+			returned[[3]float64{l.Cost, l.Resources[0], l.Resources[1]}] = true
+		}
+		assert.Equal(t, front, returned)
+	}
+}
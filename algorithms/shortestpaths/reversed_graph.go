@@ -0,0 +1,37 @@
+package shortestpaths
+
+import (
+	"github.com/obeattie/vrp/graph"
+)
+
+// reversedGraph is a read-only view of a graph with the direction of every edge reversed, allowing forward searches to
+// run backwards.
+type reversedGraph struct {
+	graph.ReadOnly
+}
+
+func (g reversedGraph) Successors(n graph.Node) []graph.Node {
+	return g.Graph.Predecessors(n)
+}
+
+func (g reversedGraph) Predecessors(n graph.Node) []graph.Node {
+	return g.Graph.Successors(n)
+}
+
+func (g reversedGraph) EdgeTo(n, successor graph.Node) *graph.Edge {
+	e := g.Graph.EdgeTo(successor, n)
+	if e == nil {
+		return nil
+	}
+	return &graph.Edge{
+		H:        e.T,
+		T:        e.H,
+		Cost:     e.Cost,
+		Capacity: e.Capacity,
+	}
+}
+
+// Copy returns a mutable copy of the reversed graph.
+func (g reversedGraph) Copy() graph.Graph {
+	return graph.CopyOf(g)
+}
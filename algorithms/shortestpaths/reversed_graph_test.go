@@ -0,0 +1,35 @@
+package shortestpaths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestReversedGraph(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 1}, T: graph.Node{Id: 2}, Cost: 3})
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 2}, T: graph.Node{Id: 3}, Cost: 4})
+	r := reversedGraph{graph.ReadOnly{Graph: g}}
+
+	assert.Len(t, r.Successors(graph.Node{Id: 2}), 1)
+	assert.Equal(t, 1, r.Successors(graph.Node{Id: 2})[0].ID())
+	assert.Equal(t, 3, r.Predecessors(graph.Node{Id: 2})[0].ID())
+	assert.Nil(t, r.EdgeTo(graph.Node{Id: 1}, graph.Node{Id: 2}))
+	e := r.EdgeTo(graph.Node{Id: 2}, graph.Node{Id: 1})
+	assert.Equal(t, 2, e.H.ID())
+	assert.Equal(t, 1, e.T.ID())
+	assert.Equal(t, 3.0, e.Cost)
+
+	p, err := DijkstraPath(r, graph.Node{Id: 3}, graph.Node{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 7.0, p.Cost)
+
+	c := r.Copy()
+	assert.NotNil(t, c.EdgeTo(graph.Node{Id: 3}, graph.Node{Id: 2}))
+	assert.Nil(t, c.EdgeTo(graph.Node{Id: 2}, graph.Node{Id: 3}))
+	assert.Panics(t, func() { r.AddNode(graph.Node{Id: 4}) })
+	assert.Nil(t, g.EdgeTo(graph.Node{Id: 2}, graph.Node{Id: 1})) // The underlying graph is untouched
+}
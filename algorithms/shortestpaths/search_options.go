@@ -0,0 +1,67 @@
+package shortestpaths
+
+import (
+	"context"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// SearchOptions restrict the paths a search may return.
+type SearchOptions struct {
+	// AvoidNodes are nodes the path must not visit.
+	AvoidNodes []graph.Node
+	// AvoidEdges are edges the path must not traverse. Only their heads and tails are considered.
+	AvoidEdges []*graph.Edge
+	// Via are nodes the path must visit, in order, between the source and target.
+	Via []graph.Node
+}
+
+// DijkstraPathWithOptions returns the shortest path from source to target which satisfies the given options. The graph
+// is neither copied nor modified, so concurrent searches may share it.
+//
+// The path is made up of the shortest legs between consecutive via nodes, so it may revisit nodes (though never an
+// avoided one). If the source, target or a via node is avoided, or any leg cannot be completed, ErrUnreachable is
+// returned.
+func DijkstraPathWithOptions(g graph.Graph, source, target graph.Node, opts SearchOptions) (Path, error) {
+	return DijkstraPathWithOptionsContext(context.Background(), g, source, target, opts)
+}
+
+// DijkstraPathWithOptionsContext is like DijkstraPathWithOptions, but stops searching (returning ctx.Err()) once the
+// context is done.
+func DijkstraPathWithOptionsContext(ctx context.Context, g graph.Graph, source, target graph.Node, opts SearchOptions) (Path, error) {
+	stops := make([]graph.Node, 0, len(opts.Via)+2)
+	stops = append(stops, source)
+	stops = append(stops, opts.Via...)
+	stops = append(stops, target)
+
+	view := graph.Graph(g)
+	if len(opts.AvoidNodes) > 0 || len(opts.AvoidEdges) > 0 {
+		filtered := newFilteredGraph(g)
+		for _, n := range opts.AvoidNodes {
+			filtered.hideNode(n)
+		}
+		for _, e := range opts.AvoidEdges {
+			filtered.hideEdge(e.H, e.T)
+		}
+		for _, n := range stops {
+			if filtered.hiddenNodes[n.ID()] {
+				return Path{}, ErrUnreachable
+			}
+		}
+		view = filtered
+	}
+
+	result := Path{
+		Nodes: []graph.Node{source},
+	}
+	for i := 1; i < len(stops); i++ {
+		leg, err := DijkstraPathContext(ctx, view, stops[i-1], stops[i])
+		if err != nil {
+			return Path{}, err
+		}
+		result.Nodes = append(result.Nodes, leg.Nodes[1:]...)
+		result.Edges = append(result.Edges, leg.Edges...)
+		result.Cost += leg.Cost
+	}
+	return result, nil
+}
@@ -0,0 +1,161 @@
+package shortestpaths
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestSearchOptions(t *testing.T) {
+	suite.Run(t, new(SearchOptionsTestSuite))
+}
+
+type SearchOptionsTestSuite struct {
+	suite.Suite
+	g graph.Graph
+}
+
+func (suite *SearchOptionsTestSuite) SetupTest() {
+	// A 3x3 grid, numbered:
+	// 1 2 3
+	// 4 5 6
+	// 7 8 9
+	// with unit costs in both directions
+	g := graph.NewGraph()
+	for _, e := range []nodePrototype{
+		{1, 2}, {2, 3}, {4, 5}, {5, 6}, {7, 8}, {8, 9},
+		{1, 4}, {4, 7}, {2, 5}, {5, 8}, {3, 6}, {6, 9},
+	} {
+		g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: e.srcId}, T: graph.Node{Id: e.targetId}, Cost: 1})
+		g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: e.targetId}, T: graph.Node{Id: e.srcId}, Cost: 1})
+	}
+	suite.g = g
+}
+
+func (suite *SearchOptionsTestSuite) ids(nodes []graph.Node) []int {
+	result := make([]int, len(nodes))
+	for i, n := range nodes {
+		result[i] = n.ID()
+	}
+	return result
+}
+
+func (suite *SearchOptionsTestSuite) TestNoOptions() {
+	t, g := suite.T(), suite.g
+
+	path, err := DijkstraPathWithOptions(g, graph.Node{Id: 1}, graph.Node{Id: 9}, SearchOptions{})
+	assert.NoError(t, err)
+	expected, _ := DijkstraPath(g, graph.Node{Id: 1}, graph.Node{Id: 9})
+	assert.Equal(t, expected.Cost, path.Cost)
+	assert.Len(t, path.Nodes, len(expected.Nodes))
+}
+
+func (suite *SearchOptionsTestSuite) TestAvoid() {
+	t, g := suite.T(), suite.g
+
+	path, err := DijkstraPathWithOptions(g, graph.Node{Id: 4}, graph.Node{Id: 6}, SearchOptions{
+		AvoidNodes: []graph.Node{{Id: 5}},
+		AvoidEdges: []*graph.Edge{{H: graph.Node{Id: 4}, T: graph.Node{Id: 1}}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 7, 8, 9, 6}, suite.ids(path.Nodes))
+	assert.Equal(t, 4.0, path.Cost)
+	assert.Len(t, path.Edges, 4)
+
+	// The graph itself is untouched
+	assert.NotNil(t, g.EdgeTo(graph.Node{Id: 4}, graph.Node{Id: 1}))
+	assert.True(t, g.NodeExists(graph.Node{Id: 5}))
+
+	_, err = DijkstraPathWithOptions(g, graph.Node{Id: 1}, graph.Node{Id: 9}, SearchOptions{
+		AvoidNodes: []graph.Node{{Id: 6}, {Id: 8}},
+	})
+	assert.Equal(t, ErrUnreachable, err)
+	_, err = DijkstraPathWithOptions(g, graph.Node{Id: 1}, graph.Node{Id: 1}, SearchOptions{
+		AvoidNodes: []graph.Node{{Id: 1}},
+	})
+	assert.Equal(t, ErrUnreachable, err)
+}
+
+func (suite *SearchOptionsTestSuite) TestVia() {
+	t, g := suite.T(), suite.g
+
+	path, err := DijkstraPathWithOptions(g, graph.Node{Id: 1}, graph.Node{Id: 2}, SearchOptions{
+		Via: []graph.Node{{Id: 7}, {Id: 3}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0+4.0+1.0, path.Cost)
+	assert.Equal(t, 1, path.Source().ID())
+	assert.Equal(t, 2, path.Target().ID())
+	assert.Len(t, path.Nodes, 8)
+	assert.Len(t, path.Edges, 7)
+	for i, e := range path.Edges {
+		assert.Equal(t, path.Nodes[i].ID(), e.H.ID())
+		assert.Equal(t, path.Nodes[i+1].ID(), e.T.ID())
+	}
+	assert.Equal(t, 7, path.Nodes[2].ID())
+
+	// The path may revisit nodes, but never avoided ones
+	path, err = DijkstraPathWithOptions(g, graph.Node{Id: 2}, graph.Node{Id: 8}, SearchOptions{
+		Via:        []graph.Node{{Id: 1}},
+		AvoidNodes: []graph.Node{{Id: 5}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1, 4, 7, 8}, suite.ids(path.Nodes))
+
+	_, err = DijkstraPathWithOptions(g, graph.Node{Id: 1}, graph.Node{Id: 9}, SearchOptions{
+		Via:        []graph.Node{{Id: 5}},
+		AvoidNodes: []graph.Node{{Id: 5}},
+	})
+	assert.Equal(t, ErrUnreachable, err)
+}
+
+func (suite *SearchOptionsTestSuite) TestCancellation() {
+	t, g := suite.T(), gridGraph(50)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := DijkstraPathWithOptionsContext(ctx, g, graph.Node{Id: 1}, graph.Node{Id: 2500}, SearchOptions{})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func (suite *SearchOptionsTestSuite) TestMatchesCopiedGraph() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(15))
+	g := randomGeometricGraph(rng, 200, 1200)
+	nodes := g.NodeList()
+
+	reachable := 0
+	for i := 0; i < 20; i++ {
+		opts := SearchOptions{}
+		copied := g.Copy()
+		for j := 0; j < 10; j++ {
+			n := nodes[rng.Intn(len(nodes))]
+			opts.AvoidNodes = append(opts.AvoidNodes, n)
+			copied.RemoveNode(n)
+		}
+		remaining := copied.NodeList()
+		for j := 0; j < 20; j++ {
+			n := remaining[rng.Intn(len(remaining))]
+			if successors := copied.Successors(n); len(successors) > 0 {
+				edge := copied.EdgeTo(n, successors[rng.Intn(len(successors))])
+				opts.AvoidEdges = append(opts.AvoidEdges, edge)
+				copied.RemoveDirectedEdge(edge)
+			}
+		}
+		source, target := remaining[rng.Intn(len(remaining))], remaining[rng.Intn(len(remaining))]
+
+		expected, expectedErr := DijkstraPath(copied, source, target)
+		path, err := DijkstraPathWithOptions(g, source, target, opts)
+		assert.Equal(t, expectedErr, err)
+		assert.InDelta(t, expected.Cost, path.Cost, 1e-9)
+		if err == nil {
+			reachable++
+		}
+	}
+	assert.NotZero(t, reachable)
+}
@@ -0,0 +1,59 @@
+package shortestpaths
+
+import (
+	"math"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// A ShortestPathTree holds the shortest paths from a single source to every node reached by a search.
+type ShortestPathTree struct {
+	// Source is the root of the tree.
+	Source graph.Node
+	// Costs holds the cost of the shortest path from the source to each node in the tree (including the source).
+	Costs map[graph.Node]float64
+	// Predecessors holds the node preceding each node on its shortest path from the source. The source has no
+	// predecessor.
+	Predecessors map[graph.Node]graph.Node
+	g            graph.Graph
+}
+
+// Reachable returns whether the node is in the tree.
+func (t *ShortestPathTree) Reachable(n graph.Node) bool {
+	_, ok := t.Costs[n]
+	return ok
+}
+
+// Cost returns the cost of the shortest path from the source to n, or infinity if n is not in the tree.
+func (t *ShortestPathTree) Cost(n graph.Node) float64 {
+	if cost, ok := t.Costs[n]; ok {
+		return cost
+	}
+	return math.Inf(0)
+}
+
+// Nodes returns all nodes in the tree (in no particular order).
+func (t *ShortestPathTree) Nodes() []graph.Node {
+	result := make([]graph.Node, 0, len(t.Costs))
+	for n := range t.Costs {
+		result = append(result, n)
+	}
+	return result
+}
+
+// PathTo returns the shortest path from the source to n. If n is not in the tree, ErrUnreachable is returned.
+func (t *ShortestPathTree) PathTo(n graph.Node) (Path, error) {
+	if !t.Reachable(n) {
+		return Path{}, ErrUnreachable
+	}
+
+	nodes := []graph.Node{n}
+	for n != t.Source {
+		n = t.Predecessors[n]
+		nodes = append(nodes, n)
+	}
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+	return NewPath(t.g, nodes)
+}
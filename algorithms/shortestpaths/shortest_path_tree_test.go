@@ -0,0 +1,93 @@
+package shortestpaths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestShortestPathTree(t *testing.T) {
+	suite.Run(t, new(ShortestPathTreeTestSuite))
+}
+
+type ShortestPathTreeTestSuite struct {
+	suite.Suite
+	tree *ShortestPathTree
+}
+
+func (suite *ShortestPathTreeTestSuite) SetupTest() {
+	g := graph.NewGraph()
+	edges := [...]struct {
+		srcId, targetId int
+		cost            float64
+	}{
+		{1, 2, 1},
+		{1, 3, 4},
+		{2, 3, 1},
+		{3, 4, 1},
+		{5, 1, 1},
+	}
+
+	for _, e := range edges {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: e.srcId},
+			T:    graph.Node{Id: e.targetId},
+			Cost: e.cost,
+		})
+	}
+
+	tree, err := SingleSourceDijkstra(g, graph.Node{Id: 1}, math.Inf(0))
+	assert.NoError(suite.T(), err)
+	suite.tree = tree
+}
+
+func (suite *ShortestPathTreeTestSuite) TestReachable() {
+	t, tree := suite.T(), suite.tree
+
+	for _, id := range []int{1, 2, 3, 4} {
+		assert.True(t, tree.Reachable(graph.Node{Id: id}))
+	}
+	assert.False(t, tree.Reachable(graph.Node{Id: 5}))
+	assert.Len(t, tree.Nodes(), 4)
+}
+
+func (suite *ShortestPathTreeTestSuite) TestCost() {
+	t, tree := suite.T(), suite.tree
+
+	assert.Equal(t, 0.0, tree.Cost(graph.Node{Id: 1}))
+	assert.Equal(t, 1.0, tree.Cost(graph.Node{Id: 2}))
+	assert.Equal(t, 2.0, tree.Cost(graph.Node{Id: 3}))
+	assert.Equal(t, 3.0, tree.Cost(graph.Node{Id: 4}))
+	assert.True(t, math.IsInf(tree.Cost(graph.Node{Id: 5}), 1))
+}
+
+func (suite *ShortestPathTreeTestSuite) TestPredecessors() {
+	t, tree := suite.T(), suite.tree
+
+	_, ok := tree.Predecessors[graph.Node{Id: 1}]
+	assert.False(t, ok)
+	assert.Equal(t, graph.Node{Id: 1}, tree.Predecessors[graph.Node{Id: 2}])
+	assert.Equal(t, graph.Node{Id: 2}, tree.Predecessors[graph.Node{Id: 3}])
+	assert.Equal(t, graph.Node{Id: 3}, tree.Predecessors[graph.Node{Id: 4}])
+}
+
+func (suite *ShortestPathTreeTestSuite) TestPathTo() {
+	t, tree := suite.T(), suite.tree
+
+	p, err := tree.PathTo(graph.Node{Id: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []graph.Node{{Id: 1}, {Id: 2}, {Id: 3}, {Id: 4}}, p.Nodes)
+	assert.Equal(t, 3.0, p.Cost)
+
+	p, err = tree.PathTo(graph.Node{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []graph.Node{{Id: 1}}, p.Nodes)
+
+	p, err = tree.PathTo(graph.Node{Id: 5})
+	assert.Equal(t, ErrUnreachable, err)
+	assert.True(t, p.IsZero())
+}
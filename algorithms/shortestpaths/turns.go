@@ -0,0 +1,163 @@
+package shortestpaths
+
+import (
+	"container/heap"
+	"context"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// A TurnTable holds the restrictions and costs of turns: manoeuvres from one edge onto the next. Turns are identified by
+// their incoming and outgoing edges (only whose heads and tails are considered); turns which aren't in the table are
+// permitted, and free.
+type TurnTable struct {
+	// ForbidUTurns forbids every turn straight back along the edge just travelled.
+	ForbidUTurns bool
+	restricted   map[[3]int]bool    // By (from, via, to) node IDs
+	costs        map[[3]int]float64 // By (from, via, to) node IDs
+}
+
+func NewTurnTable() *TurnTable {
+	return &TurnTable{
+		restricted: map[[3]int]bool{},
+		costs:      map[[3]int]float64{},
+	}
+}
+
+// turnKey identifies the turn from in onto out. The edges must meet, at in's tail and out's head.
+func turnKey(in, out *graph.Edge) [3]int {
+	return [3]int{in.H.ID(), in.T.ID(), out.T.ID()}
+}
+
+// Restrict forbids the turn from in onto out.
+func (t *TurnTable) Restrict(in, out *graph.Edge) {
+	t.restricted[turnKey(in, out)] = true
+}
+
+// SetCost sets the cost of the turn from in onto out, which is added to the cost of any path making it.
+func (t *TurnTable) SetCost(in, out *graph.Edge, cost float64) {
+	t.costs[turnKey(in, out)] = cost
+}
+
+// Allowed returns whether the turn from in onto out is permitted.
+func (t *TurnTable) Allowed(in, out *graph.Edge) bool {
+	if t.ForbidUTurns && in.H.ID() == out.T.ID() {
+		return false
+	}
+	return !t.restricted[turnKey(in, out)]
+}
+
+// Cost returns the cost of the turn from in onto out.
+func (t *TurnTable) Cost(in, out *graph.Edge) float64 {
+	return t.costs[turnKey(in, out)]
+}
+
+// TurnRestrictedPath returns the shortest path from source to target which makes no restricted turns. Turn costs are
+// included in the cost of the path.
+//
+// Since the best way to reach a node depends on which edge it's reached by, the search runs over edges rather than
+// nodes (as a node-based search over the edge-based expansion of the graph would). The path may therefore visit a node
+// more than once: going around the block to avoid a banned left turn, say.
+func TurnRestrictedPath(g graph.Graph, source, target graph.Node, turns *TurnTable) (Path, error) {
+	return TurnRestrictedPathContext(context.Background(), g, source, target, turns)
+}
+
+// TurnRestrictedPathContext is like TurnRestrictedPath, but stops searching (returning ctx.Err()) once the context is
+// done.
+func TurnRestrictedPathContext(ctx context.Context, g graph.Graph, source, target graph.Node, turns *TurnTable) (Path, error) {
+	if source == target {
+		return Path{Nodes: []graph.Node{source}, Edges: []*graph.Edge{}}, nil
+	}
+
+	final := map[[2]int]bool{} // Edges (by head and tail ID) whose shortest arrival is known
+	fringe := &turnQueue{}
+	for _, w := range g.Successors(source) {
+		edge := g.EdgeTo(source, w)
+		if edge.Cost < 0 {
+			return Path{}, ErrContradiction
+		}
+		heap.Push(fringe, &turnState{edge: edge, cost: edge.Cost})
+	}
+
+	for i := 0; fringe.Len() > 0; i++ {
+		if i%cancellationInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return Path{}, err
+			}
+		}
+
+		s := heap.Pop(fringe).(*turnState)
+		key := [2]int{s.edge.H.ID(), s.edge.T.ID()}
+		if final[key] {
+			continue
+		}
+		final[key] = true
+
+		v := s.edge.T
+		if v.ID() == target.ID() {
+			edges := []*graph.Edge{}
+			for e := s; e != nil; e = e.parent {
+				edges = append(edges, e.edge)
+			}
+			path := Path{
+				Nodes: make([]graph.Node, 0, len(edges)+1),
+				Edges: make([]*graph.Edge, 0, len(edges)),
+				Cost:  s.cost,
+			}
+			path.Nodes = append(path.Nodes, source)
+			for i := len(edges) - 1; i >= 0; i-- {
+				path.Nodes = append(path.Nodes, edges[i].T)
+				path.Edges = append(path.Edges, edges[i])
+			}
+			return path, nil
+		}
+
+		for _, w := range g.Successors(v) {
+			edge := g.EdgeTo(v, w)
+			if final[[2]int{v.ID(), w.ID()}] || !turns.Allowed(s.edge, edge) {
+				continue
+			}
+			// Neither the edge nor the turn onto it may be negative: one mustn't hide the other
+			turnCost := turns.Cost(s.edge, edge)
+			if edge.Cost < 0 || turnCost < 0 {
+				return Path{}, ErrContradiction
+			}
+			heap.Push(fringe, &turnState{edge: edge, parent: s, cost: s.cost + turnCost + edge.Cost})
+		}
+	}
+
+	return Path{}, ErrUnreachable
+}
+
+// A turnState is an edge reached by a turn-restricted search, along with how it was reached
+type turnState struct {
+	edge   *graph.Edge
+	parent *turnState
+	cost   float64
+}
+
+// turnQueue is a min-heap of search states ordered by cost, for use with container/heap.
+type turnQueue []*turnState
+
+func (q turnQueue) Len() int {
+	return len(q)
+}
+
+func (q turnQueue) Less(i, j int) bool {
+	return q[i].cost < q[j].cost
+}
+
+func (q turnQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *turnQueue) Push(x interface{}) {
+	*q = append(*q, x.(*turnState))
+}
+
+func (q *turnQueue) Pop() interface{} {
+	old := *q
+	s := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return s
+}
@@ -0,0 +1,159 @@
+package shortestpaths
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestTurnRestrictedPath(t *testing.T) {
+	suite.Run(t, new(TurnRestrictedPathTestSuite))
+}
+
+type TurnRestrictedPathTestSuite struct {
+	suite.Suite
+	g graph.Graph
+}
+
+func (suite *TurnRestrictedPathTestSuite) SetupTest() {
+	// A crossroads at 5, with a block to its north-east which can be driven around (all two-way):
+	//     2 - 3
+	//     |   |
+	// 4 - 5 - 6
+	//     |
+	//     8
+	g := graph.NewGraph()
+	for _, e := range []nodePrototype{
+		{4, 5}, {5, 6}, {2, 5}, {5, 8}, {2, 3}, {3, 6},
+	} {
+		g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: e.srcId}, T: graph.Node{Id: e.targetId}, Cost: 1})
+		g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: e.targetId}, T: graph.Node{Id: e.srcId}, Cost: 1})
+	}
+	suite.g = g
+}
+
+func (suite *TurnRestrictedPathTestSuite) edge(h, t int) *graph.Edge {
+	return suite.g.EdgeTo(graph.Node{Id: h}, graph.Node{Id: t})
+}
+
+func (suite *TurnRestrictedPathTestSuite) ids(nodes []graph.Node) []int {
+	result := make([]int, len(nodes))
+	for i, n := range nodes {
+		result[i] = n.ID()
+	}
+	return result
+}
+
+func (suite *TurnRestrictedPathTestSuite) TestUnrestricted() {
+	t, g := suite.T(), suite.g
+
+	path, err := TurnRestrictedPath(g, graph.Node{Id: 8}, graph.Node{Id: 6}, NewTurnTable())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{8, 5, 6}, suite.ids(path.Nodes))
+	assert.Equal(t, 2.0, path.Cost)
+	assert.Len(t, path.Edges, 2)
+
+	path, err = TurnRestrictedPath(g, graph.Node{Id: 8}, graph.Node{Id: 8}, NewTurnTable())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{8}, suite.ids(path.Nodes))
+	assert.Equal(t, 0.0, path.Cost)
+}
+
+func (suite *TurnRestrictedPathTestSuite) TestRestriction() {
+	t, g := suite.T(), suite.g
+	turns := NewTurnTable()
+	turns.ForbidUTurns = true
+	// No right turn from 8 towards 6: go around the block instead
+	turns.Restrict(suite.edge(8, 5), suite.edge(5, 6))
+
+	path, err := TurnRestrictedPath(g, graph.Node{Id: 8}, graph.Node{Id: 6}, turns)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{8, 5, 2, 3, 6}, suite.ids(path.Nodes))
+	assert.Equal(t, 4.0, path.Cost)
+	for i, e := range path.Edges {
+		assert.Equal(t, path.Nodes[i].ID(), e.H.ID())
+		assert.Equal(t, path.Nodes[i+1].ID(), e.T.ID())
+	}
+
+	// Also ban turning from 8 towards 2: the only way is a U-turn at 4
+	turns.Restrict(suite.edge(8, 5), suite.edge(5, 2))
+	turns.ForbidUTurns = false
+	path, err = TurnRestrictedPath(g, graph.Node{Id: 8}, graph.Node{Id: 6}, turns)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{8, 5, 4, 5, 6}, suite.ids(path.Nodes))
+
+	turns.ForbidUTurns = true
+	_, err = TurnRestrictedPath(g, graph.Node{Id: 8}, graph.Node{Id: 6}, turns)
+	assert.Equal(t, ErrUnreachable, err)
+	assert.False(t, turns.Allowed(suite.edge(5, 4), suite.edge(4, 5)))
+	assert.True(t, turns.Allowed(suite.edge(8, 5), suite.edge(5, 4)))
+}
+
+func (suite *TurnRestrictedPathTestSuite) TestCosts() {
+	t, g := suite.T(), suite.g
+	turns := NewTurnTable()
+	turns.ForbidUTurns = true
+	turns.SetCost(suite.edge(8, 5), suite.edge(5, 6), 3)
+	assert.Equal(t, 3.0, turns.Cost(suite.edge(8, 5), suite.edge(5, 6)))
+	assert.Equal(t, 0.0, turns.Cost(suite.edge(8, 5), suite.edge(5, 2)))
+
+	path, err := TurnRestrictedPath(g, graph.Node{Id: 8}, graph.Node{Id: 6}, turns)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{8, 5, 2, 3, 6}, suite.ids(path.Nodes))
+	assert.Equal(t, 4.0, path.Cost)
+
+	turns.SetCost(suite.edge(8, 5), suite.edge(5, 6), 1)
+	path, err = TurnRestrictedPath(g, graph.Node{Id: 8}, graph.Node{Id: 6}, turns)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{8, 5, 6}, suite.ids(path.Nodes))
+	assert.Equal(t, 3.0, path.Cost)
+
+	turns.SetCost(suite.edge(8, 5), suite.edge(5, 6), -2)
+	_, err = TurnRestrictedPath(g, graph.Node{Id: 8}, graph.Node{Id: 6}, turns)
+	assert.Equal(t, ErrContradiction, err)
+}
+
+func (suite *TurnRestrictedPathTestSuite) TestNegativeEdgeBehindTurnCost() {
+	t, g := suite.T(), suite.g
+	g.AddDirectedEdge(&graph.Edge{H: graph.Node{Id: 5}, T: graph.Node{Id: 6}, Cost: -1})
+	turns := NewTurnTable()
+	// The turn cost outweighs the negative edge cost, but mustn't mask it
+	turns.SetCost(suite.edge(8, 5), suite.edge(5, 6), 3)
+
+	_, err := TurnRestrictedPath(g, graph.Node{Id: 8}, graph.Node{Id: 6}, turns)
+	assert.Equal(t, ErrContradiction, err)
+}
+
+func (suite *TurnRestrictedPathTestSuite) TestCancellation() {
+	t := suite.T()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := TurnRestrictedPathContext(ctx, suite.g, graph.Node{Id: 8}, graph.Node{Id: 6}, NewTurnTable())
+	assert.Equal(t, context.Canceled, err)
+}
+
+func (suite *TurnRestrictedPathTestSuite) TestMatchesDijkstraWithoutRestrictions() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(18))
+	g := randomGeometricGraph(rng, 200, 1200)
+	nodes := g.NodeList()
+
+	reachable := 0
+	for i := 0; i < 30; i++ {
+		s, u := nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))]
+		expected, expectedErr := DijkstraPath(g, s, u)
+		path, err := TurnRestrictedPath(g, s, u, NewTurnTable())
+		assert.Equal(t, expectedErr, err)
+		assert.InDelta(t, expected.Cost, path.Cost, 1e-9)
+		if err == nil {
+			reachable++
+		}
+	}
+	assert.NotZero(t, reachable)
+}
@@ -0,0 +1,67 @@
+package spanningtree
+
+import (
+	"sort"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// Kruskal returns a minimum spanning forest of the graph, treating its edges as undirected (where a pair of nodes is
+// joined in both directions, the cheaper edge is used).
+//
+// Edges are considered in increasing order of cost, and each is added to the forest unless it would form a cycle. This
+// runs in O(E log E) time, and suits sparse graphs.
+func Kruskal(g graph.Graph) SpanningForest {
+	edges := undirectedEdges(g)
+	sort.Sort(edgesByCost(edges))
+
+	result := SpanningForest{
+		Edges: []*graph.Edge{},
+	}
+	components := newDisjointSet()
+	for _, e := range edges {
+		if components.union(e.H.ID(), e.T.ID()) {
+			result.add(e)
+		}
+	}
+	return result
+}
+
+// disjointSet is a union-find structure over node IDs, with path compression and union by rank.
+type disjointSet struct {
+	parents map[int]int
+	ranks   map[int]int
+}
+
+func newDisjointSet() *disjointSet {
+	return &disjointSet{
+		parents: map[int]int{},
+		ranks:   map[int]int{},
+	}
+}
+
+func (s *disjointSet) find(x int) int {
+	parent, ok := s.parents[x]
+	if !ok || parent == x {
+		return x
+	}
+	root := s.find(parent)
+	s.parents[x] = root
+	return root
+}
+
+// union merges the sets containing a and b, returning false if they were already the same set.
+func (s *disjointSet) union(a, b int) bool {
+	a, b = s.find(a), s.find(b)
+	if a == b {
+		return false
+	}
+	if s.ranks[a] < s.ranks[b] {
+		a, b = b, a
+	}
+	s.parents[b] = a
+	if s.ranks[a] == s.ranks[b] {
+		s.ranks[a]++
+	}
+	return true
+}
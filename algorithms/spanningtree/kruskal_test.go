@@ -0,0 +1,129 @@
+package spanningtree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+type edgePrototype struct {
+	srcId, targetId int
+	cost            float64
+}
+
+func generateGraph(edges []edgePrototype) graph.Graph {
+	g := graph.NewGraph()
+	for _, e := range edges {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: e.srcId},
+			T:    graph.Node{Id: e.targetId},
+			Cost: e.cost,
+		})
+	}
+	return g
+}
+
+// randomGraph generates a graph of n nodes with m randomly-directed edges of random (and usually distinct) costs
+func randomGraph(rng *rand.Rand, n, m int) graph.Graph {
+	g := graph.NewGraph()
+	for i := 1; i <= n; i++ {
+		g.AddNode(graph.Node{Id: i})
+	}
+	for i := 0; i < m; i++ {
+		g.AddDirectedEdge(&graph.Edge{
+			H:    graph.Node{Id: 1 + rng.Intn(n)},
+			T:    graph.Node{Id: 1 + rng.Intn(n)},
+			Cost: rng.Float64() * 100,
+		})
+	}
+	return g
+}
+
+// forestGraph has two components: a square (1-4) with a diagonal, and a pair (5, 6). 7 is isolated.
+func forestGraph() graph.Graph {
+	g := generateGraph([]edgePrototype{
+		{1, 2, 1},
+		{2, 3, 2},
+		{3, 4, 3},
+		{4, 1, 4},
+		{1, 3, 2.5},
+		{3, 1, 1.5}, // Cheaper in the other direction
+		{2, 2, 0},   // Loops are ignored
+		{6, 5, 7},
+	})
+	g.AddNode(graph.Node{Id: 7})
+	return g
+}
+
+// edgeKeys returns the (lower, higher) node IDs of each edge
+func edgeKeys(edges []*graph.Edge) map[[2]int]bool {
+	result := make(map[[2]int]bool, len(edges))
+	for _, e := range edges {
+		result[pairKey(e.H, e.T)] = true
+	}
+	return result
+}
+
+func TestKruskal(t *testing.T) {
+	suite.Run(t, new(KruskalTestSuite))
+}
+
+type KruskalTestSuite struct {
+	suite.Suite
+}
+
+func (suite *KruskalTestSuite) TestForest() {
+	t := suite.T()
+
+	f := Kruskal(forestGraph())
+	assert.Equal(t, 1.0+1.5+3.0+7.0, f.Cost)
+	assert.Equal(t, map[[2]int]bool{
+		{1, 2}: true,
+		{1, 3}: true,
+		{3, 4}: true,
+		{5, 6}: true,
+	}, edgeKeys(f.Edges))
+	for _, e := range f.Edges {
+		if pairKey(e.H, e.T) == [2]int{1, 3} {
+			assert.Equal(t, 3, e.H.ID())
+		}
+	}
+}
+
+func (suite *KruskalTestSuite) TestEmpty() {
+	t := suite.T()
+
+	f := Kruskal(graph.NewGraph())
+	assert.Len(t, f.Edges, 0)
+	assert.Equal(t, 0.0, f.Cost)
+}
+
+func (suite *KruskalTestSuite) TestSpansComponents() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(19))
+
+	for i := 0; i < 10; i++ {
+		g := randomGraph(rng, 100, 150)
+		f := Kruskal(g)
+
+		// A forest has one fewer edge than nodes in each component, and joins the same components as the graph
+		components := newDisjointSet()
+		for _, e := range undirectedEdges(g) {
+			components.union(e.H.ID(), e.T.ID())
+		}
+		roots := map[int]bool{}
+		for _, n := range g.NodeList() {
+			roots[components.find(n.ID())] = true
+		}
+		assert.Len(t, f.Edges, len(g.NodeList())-len(roots))
+
+		forestComponents := newDisjointSet()
+		for _, e := range f.Edges {
+			assert.True(t, forestComponents.union(e.H.ID(), e.T.ID()), "Forest contains a cycle")
+		}
+	}
+}
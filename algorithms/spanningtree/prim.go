@@ -0,0 +1,88 @@
+package spanningtree
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+// Prim returns a minimum spanning forest of the graph, treating its edges as undirected (where a pair of nodes is
+// joined in both directions, the cheaper edge is used).
+//
+// Each tree is grown from a single node (the one with the lowest ID not yet in the forest), repeatedly adding the
+// cheapest edge which reaches a new node. Candidate edges wait in a heap until they are found to be the cheapest (or to
+// lead back into the forest), so this runs in O(E log E) time, like Kruskal. Its advantage is that each tree is
+// complete before the next is started.
+func Prim(g graph.Graph) SpanningForest {
+	nodes := g.NodeList()
+	sort.Sort(graph.NodesById(nodes))
+
+	result := SpanningForest{
+		Edges: []*graph.Edge{},
+	}
+	inForest := make(map[int]bool, len(nodes))
+	for _, root := range nodes {
+		if inForest[root.ID()] {
+			continue
+		}
+
+		fringe := &edgeQueue{}
+		// visit adds n to the tree, and queues the edges leading out of it
+		visit := func(n graph.Node) {
+			inForest[n.ID()] = true
+			for _, successor := range g.Successors(n) {
+				if !inForest[successor.ID()] {
+					heap.Push(fringe, edgeQueueItem{edge: g.EdgeTo(n, successor), node: successor})
+				}
+			}
+			for _, predecessor := range g.Predecessors(n) {
+				if !inForest[predecessor.ID()] {
+					heap.Push(fringe, edgeQueueItem{edge: g.EdgeTo(predecessor, n), node: predecessor})
+				}
+			}
+		}
+
+		visit(root)
+		for fringe.Len() > 0 {
+			item := heap.Pop(fringe).(edgeQueueItem)
+			if inForest[item.node.ID()] {
+				continue
+			}
+			result.add(item.edge)
+			visit(item.node)
+		}
+	}
+	return result
+}
+
+type edgeQueueItem struct {
+	edge *graph.Edge
+	node graph.Node // The node the edge would add to the tree
+}
+
+// edgeQueue is a min-heap of edges ordered by edgeLess, for use with container/heap.
+type edgeQueue []edgeQueueItem
+
+func (q edgeQueue) Len() int {
+	return len(q)
+}
+
+func (q edgeQueue) Less(i, j int) bool {
+	return edgeLess(q[i].edge, q[j].edge)
+}
+
+func (q edgeQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *edgeQueue) Push(x interface{}) {
+	*q = append(*q, x.(edgeQueueItem))
+}
+
+func (q *edgeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,54 @@
+package spanningtree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/obeattie/vrp/graph"
+)
+
+func TestPrim(t *testing.T) {
+	suite.Run(t, new(PrimTestSuite))
+}
+
+type PrimTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PrimTestSuite) TestForest() {
+	t := suite.T()
+
+	f := Prim(forestGraph())
+	assert.Equal(t, 1.0+1.5+3.0+7.0, f.Cost)
+	assert.Equal(t, map[[2]int]bool{
+		{1, 2}: true,
+		{1, 3}: true,
+		{3, 4}: true,
+		{5, 6}: true,
+	}, edgeKeys(f.Edges))
+}
+
+func (suite *PrimTestSuite) TestEmpty() {
+	t := suite.T()
+
+	f := Prim(graph.NewGraph())
+	assert.Len(t, f.Edges, 0)
+	assert.Equal(t, 0.0, f.Cost)
+}
+
+func (suite *PrimTestSuite) TestMatchesKruskal() {
+	t := suite.T()
+	rng := rand.New(rand.NewSource(19))
+
+	for i := 0; i < 10; i++ {
+		g := randomGraph(rng, 100, 150+i*50)
+		prim, kruskal := Prim(g), Kruskal(g)
+
+		assert.InDelta(t, kruskal.Cost, prim.Cost, 1e-9)
+		// Costs are distinct, so the forest is unique
+		assert.Equal(t, edgeKeys(kruskal.Edges), edgeKeys(prim.Edges))
+	}
+}
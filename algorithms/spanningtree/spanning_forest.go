@@ -0,0 +1,77 @@
+// Package spanningtree finds minimum spanning trees (or, for disconnected graphs, forests) of graphs, treating their
+// edges as undirected.
+package spanningtree
+
+import (
+	"github.com/obeattie/vrp/graph"
+)
+
+// A SpanningForest is a minimum spanning forest: a minimum spanning tree of each connected component of a graph.
+type SpanningForest struct {
+	// Edges are the edges of the forest, as they appear in the graph (so their direction is arbitrary).
+	Edges []*graph.Edge
+	// Cost is the total cost of the edges.
+	Cost float64
+}
+
+func (f *SpanningForest) add(e *graph.Edge) {
+	f.Edges = append(f.Edges, e)
+	f.Cost += e.Cost
+}
+
+// undirectedEdges returns one edge for each pair of adjacent nodes: the cheaper, if they're joined in both directions.
+// Loops are ignored.
+func undirectedEdges(g graph.Graph) []*graph.Edge {
+	positions := map[[2]int]int{} // Position of each pair's edge within result, by (lower, higher) node ID
+	result := []*graph.Edge{}
+	for _, n := range g.NodeList() {
+		for _, successor := range g.Successors(n) {
+			if n.ID() == successor.ID() {
+				continue
+			}
+			e := g.EdgeTo(n, successor)
+			k := pairKey(n, successor)
+			if i, ok := positions[k]; !ok {
+				positions[k] = len(result)
+				result = append(result, e)
+			} else if e.Cost < result[i].Cost {
+				result[i] = e
+			}
+		}
+	}
+	return result
+}
+
+func pairKey(a, b graph.Node) [2]int {
+	if a.ID() > b.ID() {
+		a, b = b, a
+	}
+	return [2]int{a.ID(), b.ID()}
+}
+
+// edgeLess orders edges by cost, then by the IDs of their nodes (for determinism)
+func edgeLess(a, b *graph.Edge) bool {
+	if a.Cost != b.Cost {
+		return a.Cost < b.Cost
+	}
+	aKey, bKey := pairKey(a.H, a.T), pairKey(b.H, b.T)
+	if aKey[0] != bKey[0] {
+		return aKey[0] < bKey[0]
+	}
+	return aKey[1] < bKey[1]
+}
+
+// edgesByCost sorts edges in increasing order, by edgeLess
+type edgesByCost []*graph.Edge
+
+func (e edgesByCost) Len() int {
+	return len(e)
+}
+
+func (e edgesByCost) Less(i, j int) bool {
+	return edgeLess(e[i], e[j])
+}
+
+func (e edgesByCost) Swap(i, j int) {
+	e[i], e[j] = e[j], e[i]
+}
@@ -7,6 +7,8 @@ import (
 type Edge struct {
 	H, T Node
 	Cost float64
+	// Capacity is the greatest flow the edge can carry, as used by flow algorithms.
+	Capacity float64
 }
 
 func (e Edge) Head() graphlib.Node {
@@ -88,6 +88,11 @@ func (g *graphImpl) graphEdgeToEdge(e graphlib.Edge) *Edge {
 	if result, ok := e.(*Edge); ok {
 		return result
 	} else if we, ok := (e.(concretegraphlib.WeightedEdge)); ok {
+		if inner, ok := we.Edge.(*Edge); ok { // Preserve any other attributes of the edge
+			result := *inner
+			result.Cost = we.Cost
+			return &result
+		}
 		return &Edge{
 			H:    g.graphNodeToNode(we.Head()),
 			T:    g.graphNodeToNode(we.Tail()),
@@ -209,13 +214,5 @@ func (g *graphImpl) RemoveDirectedEdge(e *Edge) {
 }
 
 func (g *graphImpl) Copy() Graph {
-	result := NewGraph()
-	nodes := g.NodeList()
-	for _, n := range nodes {
-		result.AddNode(n)
-		for _, predecessor := range g.Predecessors(n) {
-			result.AddDirectedEdge(g.EdgeTo(predecessor, n))
-		}
-	}
-	return result
+	return CopyOf(g)
 }
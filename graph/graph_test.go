@@ -90,6 +90,15 @@ func (suite *GraphTestSuite) TestEdgeBetweenWeights() {
 	assert.Equal(t, 2, g.EdgeBetween(Node{Id: 3}, Node{Id: 3}).Cost)
 }
 
+func (suite *GraphTestSuite) TestEdgeCapacity() {
+	t, g := suite.T(), suite.g
+
+	assert.Equal(t, 0.0, g.EdgeTo(Node{Id: 1}, Node{Id: 3}).Capacity)
+	g.AddDirectedEdge(&Edge{H: Node{Id: 1}, T: Node{Id: 2}, Cost: 1, Capacity: 5})
+	assert.Equal(t, 5.0, g.EdgeTo(Node{Id: 1}, Node{Id: 2}).Capacity)
+	assert.Equal(t, 5.0, g.Copy().EdgeTo(Node{Id: 1}, Node{Id: 2}).Capacity)
+}
+
 func (suite *GraphTestSuite) TestSuccessors() {
 	t, g := suite.T(), suite.g
 
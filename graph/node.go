@@ -13,3 +13,18 @@ func (n Node) ID() int {
 func (n Node) IsZero() bool {
 	return n.Id == 0
 }
+
+// NodesById sorts nodes in increasing order of ID
+type NodesById []Node
+
+func (n NodesById) Len() int {
+	return len(n)
+}
+
+func (n NodesById) Less(i, j int) bool {
+	return n[i].ID() < n[j].ID()
+}
+
+func (n NodesById) Swap(i, j int) {
+	n[i], n[j] = n[j], n[i]
+}
@@ -0,0 +1,42 @@
+package graph
+
+// ReadOnly wraps a Graph, implementing its mutating methods by panicking. It is meant to be embedded in views of a
+// graph, which override whichever other methods they need to change what the graph looks like.
+//
+// A view's Copy method should return CopyOf the view itself: ReadOnly's own Copy copies the wrapped graph, unaware of
+// anything the view has overridden.
+type ReadOnly struct {
+	Graph
+}
+
+func (g ReadOnly) NewNode() Node {
+	panic("graph: read-only graphs cannot be modified")
+}
+
+func (g ReadOnly) AddNode(Node) {
+	panic("graph: read-only graphs cannot be modified")
+}
+
+func (g ReadOnly) RemoveNode(Node) {
+	panic("graph: read-only graphs cannot be modified")
+}
+
+func (g ReadOnly) AddDirectedEdge(*Edge) {
+	panic("graph: read-only graphs cannot be modified")
+}
+
+func (g ReadOnly) RemoveDirectedEdge(*Edge) {
+	panic("graph: read-only graphs cannot be modified")
+}
+
+// CopyOf returns a mutable copy of the nodes and edges of g, as its NodeList, Predecessors and EdgeTo methods see them.
+func CopyOf(g Graph) Graph {
+	result := NewGraph()
+	for _, n := range g.NodeList() {
+		result.AddNode(n)
+		for _, predecessor := range g.Predecessors(n) {
+			result.AddDirectedEdge(g.EdgeTo(predecessor, n))
+		}
+	}
+	return result
+}
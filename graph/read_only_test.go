@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// evenGraph hides the edges of a graph which lead to odd-numbered nodes
+type evenGraph struct {
+	ReadOnly
+}
+
+func (g evenGraph) Predecessors(n Node) []Node {
+	if n.ID()%2 != 0 {
+		return nil
+	}
+	return g.Graph.Predecessors(n)
+}
+
+func (g evenGraph) Copy() Graph {
+	return CopyOf(g)
+}
+
+func TestReadOnly(t *testing.T) {
+	g := NewGraph()
+	for i := 1; i < 4; i++ {
+		g.AddDirectedEdge(&Edge{H: Node{Id: i}, T: Node{Id: i + 1}, Cost: float64(i), Capacity: 1})
+	}
+	view := evenGraph{ReadOnly{Graph: g}}
+
+	assert.Panics(t, func() { view.NewNode() })
+	assert.Panics(t, func() { view.AddNode(Node{Id: 5}) })
+	assert.Panics(t, func() { view.RemoveNode(Node{Id: 1}) })
+	assert.Panics(t, func() { view.AddDirectedEdge(&Edge{H: Node{Id: 4}, T: Node{Id: 1}}) })
+	assert.Panics(t, func() { view.RemoveDirectedEdge(&Edge{H: Node{Id: 1}, T: Node{Id: 2}}) })
+	assert.Len(t, g.NodeList(), 4)
+
+	c := view.Copy()
+	assert.Len(t, c.NodeList(), 4)
+	assert.Equal(t, &Edge{H: Node{Id: 1}, T: Node{Id: 2}, Cost: 1, Capacity: 1}, c.EdgeTo(Node{Id: 1}, Node{Id: 2}))
+	assert.Nil(t, c.EdgeTo(Node{Id: 2}, Node{Id: 3}))
+	assert.Equal(t, &Edge{H: Node{Id: 3}, T: Node{Id: 4}, Cost: 3, Capacity: 1}, c.EdgeTo(Node{Id: 3}, Node{Id: 4}))
+
+	// The copy is independent of the original
+	c.AddNode(Node{Id: 5})
+	assert.False(t, g.NodeExists(Node{Id: 5}))
+}
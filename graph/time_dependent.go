@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+var (
+	ErrProfileEmpty        = errors.New("Profile has no breakpoints")
+	ErrProfileOutOfBounds  = errors.New("Profile breakpoints must be in increasing order within a day")
+	ErrProfileNotFIFO      = errors.New("Profile is not FIFO: leaving later could mean arriving earlier")
+	ErrProfileNegativeTime = errors.New("Profile has a negative travel time")
+)
+
+// A Profile gives the time taken to traverse an edge, depending on when it is entered.
+//
+// Profiles should have the FIFO (first-in, first-out) property: entering an edge later never means leaving it earlier.
+// Time-dependent shortest path searches are only correct over FIFO profiles.
+type Profile interface {
+	TravelTime(at time.Time) time.Duration
+}
+
+// A Breakpoint is the travel time of an edge when it is entered at a given time of day.
+type Breakpoint struct {
+	// Offset is the time of day, as the time a clock shows past midnight.
+	Offset     time.Duration
+	TravelTime time.Duration
+}
+
+// PiecewiseLinearProfile is a Profile which repeats daily, interpolating linearly between breakpoints (and wrapping
+// around from the last breakpoint of one day to the first of the next). Times of day are taken in the location of the
+// time the edge is entered.
+type PiecewiseLinearProfile struct {
+	breakpoints []Breakpoint
+}
+
+// NewPiecewiseLinearProfile returns a profile interpolating between the given breakpoints, which must be in increasing
+// order of offset within a day. The profile must be FIFO.
+func NewPiecewiseLinearProfile(breakpoints ...Breakpoint) (*PiecewiseLinearProfile, error) {
+	if len(breakpoints) == 0 {
+		return nil, ErrProfileEmpty
+	}
+	for i, b := range breakpoints {
+		if b.Offset < 0 || b.Offset >= day || (i > 0 && b.Offset <= breakpoints[i-1].Offset) {
+			return nil, ErrProfileOutOfBounds
+		} else if b.TravelTime < 0 {
+			return nil, ErrProfileNegativeTime
+		}
+	}
+
+	// Between two breakpoints, travel time mustn't fall faster than the clock advances
+	for i, b := range breakpoints {
+		next := breakpoints[(i+1)%len(breakpoints)]
+		elapsed := next.Offset - b.Offset
+		if elapsed <= 0 {
+			elapsed += day
+		}
+		if b.TravelTime-next.TravelTime > elapsed {
+			return nil, ErrProfileNotFIFO
+		}
+	}
+
+	return &PiecewiseLinearProfile{
+		breakpoints: append([]Breakpoint(nil), breakpoints...),
+	}, nil
+}
+
+// Breakpoints returns the breakpoints of the profile.
+func (p *PiecewiseLinearProfile) Breakpoints() []Breakpoint {
+	return append([]Breakpoint(nil), p.breakpoints...)
+}
+
+func (p *PiecewiseLinearProfile) TravelTime(at time.Time) time.Duration {
+	// The time of day on the clock, which isn't the time elapsed since midnight on days when the clocks change
+	h, m, s := at.Clock()
+	offset := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second +
+		time.Duration(at.Nanosecond())
+
+	// Find the breakpoints either side of offset, wrapping around midnight if needed
+	n := len(p.breakpoints)
+	i := sort.Search(n, func(i int) bool {
+		return p.breakpoints[i].Offset > offset
+	})
+	before, after := p.breakpoints[(i+n-1)%n], p.breakpoints[i%n]
+	start, end := before.Offset, after.Offset
+	if start > offset {
+		start -= day
+	}
+	if end <= start {
+		end += day
+	}
+
+	fraction := float64(offset-start) / float64(end-start)
+	return before.TravelTime + time.Duration(fraction*float64(after.TravelTime-before.TravelTime))
+}
+
+// A TimeDependentGraph is a Graph in which the time taken to traverse each edge depends on when it is entered.
+type TimeDependentGraph interface {
+	Graph
+
+	// SetProfile sets the profile of the edge from head to tail. A nil profile removes it.
+	SetProfile(head, tail Node, p Profile)
+	// Profile returns the profile of the edge from head to tail, if it has one.
+	Profile(head, tail Node) Profile
+	// TravelTime returns the time taken to traverse an edge when entering it at the given time. Edges without a profile
+	// take their cost in milliseconds, as in the graphs returned by route.Route.Graph.
+	TravelTime(e *Edge, at time.Time) time.Duration
+}
+
+type timeDependentGraphImpl struct {
+	Graph
+	sync.RWMutex
+	profiles map[[2]int]Profile // By head and tail ID
+}
+
+// NewTimeDependentGraph returns a TimeDependentGraph over g, initially without any profiles.
+func NewTimeDependentGraph(g Graph) TimeDependentGraph {
+	return &timeDependentGraphImpl{
+		Graph:    g,
+		profiles: map[[2]int]Profile{},
+	}
+}
+
+func (g *timeDependentGraphImpl) SetProfile(head, tail Node, p Profile) {
+	g.Lock()
+	defer g.Unlock()
+
+	if p == nil {
+		delete(g.profiles, [2]int{head.ID(), tail.ID()})
+	} else {
+		g.profiles[[2]int{head.ID(), tail.ID()}] = p
+	}
+}
+
+func (g *timeDependentGraphImpl) Profile(head, tail Node) Profile {
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.profiles[[2]int{head.ID(), tail.ID()}]
+}
+
+func (g *timeDependentGraphImpl) TravelTime(e *Edge, at time.Time) time.Duration {
+	if p := g.Profile(e.H, e.T); p != nil {
+		return p.TravelTime(at)
+	}
+	return time.Duration(e.Cost * float64(time.Millisecond))
+}
+
+// Copy returns a copy of the graph, including its profiles.
+func (g *timeDependentGraphImpl) Copy() Graph {
+	g.RLock()
+	defer g.RUnlock()
+
+	result := &timeDependentGraphImpl{
+		Graph:    g.Graph.Copy(),
+		profiles: make(map[[2]int]Profile, len(g.profiles)),
+	}
+	for k, p := range g.profiles {
+		result.profiles[k] = p
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTimeDependentGraph(t *testing.T) {
+	suite.Run(t, new(TimeDependentGraphTestSuite))
+}
+
+type TimeDependentGraphTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TimeDependentGraphTestSuite) at(hour, minute int) time.Time {
+	return time.Date(2016, 3, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func (suite *TimeDependentGraphTestSuite) TestPiecewiseLinearProfile() {
+	t := suite.T()
+	p, err := NewPiecewiseLinearProfile(
+		Breakpoint{Offset: 7 * time.Hour, TravelTime: 10 * time.Minute},
+		Breakpoint{Offset: 8 * time.Hour, TravelTime: 30 * time.Minute},
+		Breakpoint{Offset: 10 * time.Hour, TravelTime: 10 * time.Minute},
+		Breakpoint{Offset: 18 * time.Hour, TravelTime: 20 * time.Minute})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 10*time.Minute, p.TravelTime(suite.at(7, 0)))
+	assert.Equal(t, 20*time.Minute, p.TravelTime(suite.at(7, 30)))
+	assert.Equal(t, 30*time.Minute, p.TravelTime(suite.at(8, 0)))
+	assert.Equal(t, 20*time.Minute, p.TravelTime(suite.at(9, 0)))
+	assert.Equal(t, 15*time.Minute, p.TravelTime(suite.at(14, 0)))
+	// Wrapping around midnight, from 18:00 to 07:00
+	assert.Equal(t, 16*time.Minute, p.TravelTime(suite.at(23, 12)))
+	assert.Equal(t, 12*time.Minute, p.TravelTime(suite.at(4, 24)))
+	// Times of day are in the time's own location
+	assert.Equal(t, 30*time.Minute, p.TravelTime(suite.at(8, 0).In(time.FixedZone("", 3600)).Add(-time.Hour)))
+
+	constant, err := NewPiecewiseLinearProfile(Breakpoint{Offset: 12 * time.Hour, TravelTime: time.Minute})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, constant.TravelTime(suite.at(3, 0)))
+	assert.Equal(t, time.Minute, constant.TravelTime(suite.at(15, 0)))
+	assert.Len(t, constant.Breakpoints(), 1)
+}
+
+func (suite *TimeDependentGraphTestSuite) TestPiecewiseLinearProfileDST() {
+	t := suite.T()
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("Time zone database unavailable")
+	}
+	p, err := NewPiecewiseLinearProfile(
+		Breakpoint{Offset: 7 * time.Hour, TravelTime: 10 * time.Minute},
+		Breakpoint{Offset: 8 * time.Hour, TravelTime: 30 * time.Minute},
+		Breakpoint{Offset: 9 * time.Hour, TravelTime: 10 * time.Minute})
+	assert.NoError(t, err)
+
+	// The clocks go forward an hour at 01:00 on the first day, and back an hour at 02:00 on the second, but the peak
+	// is still at 08:00
+	for _, date := range []time.Time{
+		time.Date(2016, time.March, 27, 0, 0, 0, 0, london),
+		time.Date(2016, time.October, 30, 0, 0, 0, 0, london),
+	} {
+		y, m, d := date.Date()
+		assert.Equal(t, 30*time.Minute, p.TravelTime(time.Date(y, m, d, 8, 0, 0, 0, london)), "%v", date)
+		assert.Equal(t, 20*time.Minute, p.TravelTime(time.Date(y, m, d, 8, 30, 0, 0, london)), "%v", date)
+	}
+}
+
+func (suite *TimeDependentGraphTestSuite) TestPiecewiseLinearProfileErrors() {
+	t := suite.T()
+
+	_, err := NewPiecewiseLinearProfile()
+	assert.Equal(t, ErrProfileEmpty, err)
+	_, err = NewPiecewiseLinearProfile(
+		Breakpoint{Offset: 8 * time.Hour, TravelTime: time.Minute},
+		Breakpoint{Offset: 7 * time.Hour, TravelTime: time.Minute})
+	assert.Equal(t, ErrProfileOutOfBounds, err)
+	_, err = NewPiecewiseLinearProfile(Breakpoint{Offset: 24 * time.Hour, TravelTime: time.Minute})
+	assert.Equal(t, ErrProfileOutOfBounds, err)
+	_, err = NewPiecewiseLinearProfile(Breakpoint{Offset: time.Hour, TravelTime: -time.Minute})
+	assert.Equal(t, ErrProfileNegativeTime, err)
+	// Leaving at 08:00 would arrive at 09:00, but leaving at 08:30 would arrive at 08:40
+	_, err = NewPiecewiseLinearProfile(
+		Breakpoint{Offset: 8 * time.Hour, TravelTime: time.Hour},
+		Breakpoint{Offset: 8*time.Hour + 30*time.Minute, TravelTime: 10 * time.Minute})
+	assert.Equal(t, ErrProfileNotFIFO, err)
+	// ...including across midnight
+	_, err = NewPiecewiseLinearProfile(
+		Breakpoint{Offset: time.Hour, TravelTime: 10 * time.Minute},
+		Breakpoint{Offset: 23 * time.Hour, TravelTime: 3 * time.Hour})
+	assert.Equal(t, ErrProfileNotFIFO, err)
+}
+
+func (suite *TimeDependentGraphTestSuite) TestProfiles() {
+	t := suite.T()
+	g := NewTimeDependentGraph(NewGraph())
+	e := &Edge{H: Node{Id: 1}, T: Node{Id: 2}, Cost: 90000}
+	g.AddDirectedEdge(e)
+
+	// Without a profile, the cost is taken in milliseconds
+	assert.Nil(t, g.Profile(e.H, e.T))
+	assert.Equal(t, 90*time.Second, g.TravelTime(e, suite.at(8, 0)))
+
+	p, _ := NewPiecewiseLinearProfile(Breakpoint{Offset: 0, TravelTime: time.Hour})
+	g.SetProfile(e.H, e.T, p)
+	assert.Equal(t, p, g.Profile(e.H, e.T))
+	assert.Equal(t, time.Hour, g.TravelTime(e, suite.at(8, 0)))
+	assert.Equal(t, time.Hour, g.TravelTime(g.EdgeTo(Node{Id: 1}, Node{Id: 2}), suite.at(8, 0)))
+
+	copied, ok := g.Copy().(TimeDependentGraph)
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, copied.TravelTime(copied.EdgeTo(Node{Id: 1}, Node{Id: 2}), suite.at(8, 0)))
+
+	g.SetProfile(e.H, e.T, nil)
+	assert.Equal(t, 90*time.Second, g.TravelTime(e, suite.at(8, 0)))
+	assert.Equal(t, time.Hour, copied.TravelTime(e, suite.at(8, 0)))
+}
@@ -0,0 +1,33 @@
+package route_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/obeattie/vrp/algorithms/dag"
+	"github.com/obeattie/vrp/route"
+)
+
+// TestGraph is in an external test package because dag depends on route, through shortestpaths.
+func TestGraph(t *testing.T) {
+	ps := []route.Point{
+		{Key: "Home", IsWaypoint: true, Coordinate: route.Coordinate{-0.1555536, 51.4323465}},
+		{Key: "Clapham Junction", IsWaypoint: true, Coordinate: route.Coordinate{-0.17027, 51.46418999999999}},
+		{Key: "Soho Square", IsWaypoint: true, Coordinate: route.Coordinate{-0.1321, 51.5154}},
+	}
+	r := route.New(func(c1, c2 route.Coordinate) time.Duration { return 0 }, ps...)
+	ps = r.Points()
+	g := r.Graph()
+
+	assert.Len(t, g.NodeList(), len(ps))
+	nodes, err := dag.TopologicalSort(g)
+	assert.NoError(t, err, "Topological sort error")
+	for i, n := range nodes {
+		assert.False(t, n.IsZero())
+		p := ps[i]
+		assert.Equal(t, p.Coordinate[0], n.Lng)
+		assert.Equal(t, p.Coordinate[1], n.Lat)
+	}
+}
@@ -8,8 +8,6 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-
-	"github.com/obeattie/vrp/algorithms/dag"
 )
 
 func TestRoute(t *testing.T) {
@@ -200,22 +198,3 @@ func (suite *RouteTestSuite) TestPointGetters() {
 	assert.Equal(t, routePoints, r.RoutePoints())
 	assert.Equal(t, waypoints, r.Waypoints())
 }
-
-func (suite *RouteTestSuite) TestGraph() {
-	t := suite.T()
-
-	// Re-generate the graph using a dummy coster
-	ps := suite.r.Points()
-	r := New(func(c1, c2 Coordinate) time.Duration { return 0 }, ps...)
-	g := r.Graph()
-
-	assert.Len(t, g.NodeList(), len(ps))
-	nodes, err := dag.TopologicalSort(g)
-	assert.NoError(t, err, "Topological sort error")
-	for i, n := range nodes {
-		assert.False(t, n.IsZero())
-		p := ps[i]
-		assert.Equal(t, p.Coordinate[0], n.Lng)
-		assert.Equal(t, p.Coordinate[1], n.Lat)
-	}
-}